@@ -13,6 +13,7 @@ import (
 
 	"github.com/microsoft/ring-operator/pkg/apis"
 	"github.com/microsoft/ring-operator/pkg/controller"
+	"github.com/microsoft/ring-operator/pkg/webhook"
 
 	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
 	"github.com/operator-framework/operator-sdk/pkg/leader"
@@ -113,6 +114,12 @@ func main() {
 		os.Exit(1)
 	}
 
+	// Setup all Webhooks
+	if err := webhook.AddToManager(mgr); err != nil {
+		log.Error(err, "Cannot add webhook to manager")
+		os.Exit(1)
+	}
+
 	// Create Service object to expose the metrics port.
 	if _, err = metrics.ExposeMetricsPort(ctx, metricsPort); err != nil {
 		log.Info(err.Error())
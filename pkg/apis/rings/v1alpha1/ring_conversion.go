@@ -0,0 +1,5 @@
+package v1alpha1
+
+// Hub marks v1alpha1 as the version the other versions of Ring are converted through
+// It is the storage version, the one the operator reconciles
+func (*Ring) Hub() {}
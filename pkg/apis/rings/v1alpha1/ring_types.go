@@ -3,6 +3,7 @@ package v1alpha1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 )
 
@@ -43,9 +44,206 @@ type RingGroup struct {
 	InitialUsers []string `json:"initialUsers,omitempty"`
 }
 
+// RingMatcherType is the request attribute matched by a RingMatcher
+type RingMatcherType string
+
+const (
+	// MatchHeader matches a header with an exact value
+	MatchHeader RingMatcherType = "Header"
+	// MatchHeaderRegex matches a header with a regular expression
+	MatchHeaderRegex RingMatcherType = "HeaderRegex"
+	// MatchCookie matches a cookie with an exact value
+	MatchCookie RingMatcherType = "Cookie"
+	// MatchQuery matches a query parameter with an exact value
+	MatchQuery RingMatcherType = "Query"
+	// MatchClientIP matches the client address against a CIDR range
+	MatchClientIP RingMatcherType = "ClientIP"
+)
+
+type RingMatcher struct {
+	// Type of the matcher, one of Header, HeaderRegex, Cookie, Query or ClientIP
+	// +kubebuilder:validation:Enum=Header,HeaderRegex,Cookie,Query,ClientIP
+	Type RingMatcherType `json:"type"`
+	// Name of the header, cookie or query parameter, unused by ClientIP
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Value to match, a regular expression for HeaderRegex and a CIDR range (eg: 10.0.0.0/8) for ClientIP
+	Value string `json:"value"`
+}
+
+type RingBranchWeight struct {
+	// Branch will target the deployments with this branch tag
+	Branch string `json:"branch"`
+	// Weight is the share of the ring traffic sent to this branch, relative to the other weights
+	Weight int `json:"weight"`
+}
+
+// RingPathRewrite is how the path of a request is rewritten before it is forwarded to the ring Service
+type RingPathRewrite string
+
+const (
+	// RewriteStrip removes the ring path prefix (eg: /hello-world/v1/home.html -> /home.html)
+	RewriteStrip RingPathRewrite = "Strip"
+	// RewriteKeep forwards the path untouched
+	RewriteKeep RingPathRewrite = "Keep"
+	// RewriteReplace replaces the part of the path matching a regular expression
+	RewriteReplace RingPathRewrite = "Replace"
+)
+
+type RingPath struct {
+	// Template of the path prefix the ring is routed on, {service}, {version} and {branch} are replaced
+	// with the values of the ring routing. Defaults to /{service}/{version}
+	// +optional
+	Template string `json:"template,omitempty"`
+	// Disabled routes the ring on every path, for services told apart by their hosts only
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+	// Rewrite of the request path, one of Strip, Keep or Replace. Defaults to Strip
+	// +kubebuilder:validation:Enum=Strip,Keep,Replace
+	// +optional
+	Rewrite RingPathRewrite `json:"rewrite,omitempty"`
+	// Regex matched against the request path when rewriting with Replace
+	// Defaults to the path prefix followed by a capture of the rest of the path
+	// +optional
+	Regex string `json:"regex,omitempty"`
+	// Replacement of the path matched by the regex when rewriting with Replace, $1 expands to the first capture
+	// +optional
+	Replacement string `json:"replacement,omitempty"`
+}
+
+type RingTLSOptionRef struct {
+	// Name of the Traefik TLSOption
+	Name string `json:"name"`
+	// Namespace of the Traefik TLSOption, defaults to the namespace of the ring
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+}
+
+type RingTLSDomain struct {
+	// Main domain of the certificate requested from the resolver
+	Main string `json:"main"`
+	// SANs are the subject alternative names of the certificate requested from the resolver
+	// +optional
+	SANs []string `json:"sans,omitempty"`
+}
+
+type RingCertificate struct {
+	// IssuerName is the name of the cert-manager issuer signing the certificate
+	IssuerName string `json:"issuerName"`
+	// IssuerKind is the kind of the cert-manager issuer, Issuer or ClusterIssuer. Defaults to Issuer
+	// +kubebuilder:validation:Enum=Issuer,ClusterIssuer
+	// +optional
+	IssuerKind string `json:"issuerKind,omitempty"`
+}
+
+type RingTLS struct {
+	// SecretName of the TLS Secret holding the certificate of the ring
+	// Defaults to <ring name>-tls when the certificate is requested from cert-manager
+	// +optional
+	SecretName string `json:"secretName,omitempty"`
+	// CertResolver is the Traefik certificate resolver issuing the certificate of the ring
+	// +optional
+	CertResolver string `json:"certResolver,omitempty"`
+	// Options references the Traefik TLSOption of the ring
+	// +optional
+	Options *RingTLSOptionRef `json:"options,omitempty"`
+	// Domains are requested from the certificate resolver, defaults to the domains of the ring hosts
+	// +optional
+	Domains []RingTLSDomain `json:"domains,omitempty"`
+	// Certificate requests a cert-manager Certificate for the ring hosts, stored in SecretName
+	// +optional
+	Certificate *RingCertificate `json:"certificate,omitempty"`
+}
+
+type RingRateLimitSource struct {
+	// IPDepth groups the requests by the client address found at this depth of X-Forwarded-For,
+	// counted from the right. 0 uses the address of the connection
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	IPDepth int `json:"ipDepth,omitempty"`
+	// ExcludedIPs are skipped while looking for the client address in X-Forwarded-For
+	// +optional
+	ExcludedIPs []string `json:"excludedIPs,omitempty"`
+	// RequestHeaderName groups the requests by the value of this header instead of the client address
+	// +optional
+	RequestHeaderName string `json:"requestHeaderName,omitempty"`
+	// RequestHost groups the requests by their host instead of the client address
+	// +optional
+	RequestHost bool `json:"requestHost,omitempty"`
+}
+
+type RingRateLimit struct {
+	// Average is the number of requests allowed per period from a source
+	// +kubebuilder:validation:Minimum=1
+	Average int64 `json:"average"`
+	// Period over which Average is counted. Defaults to 1s
+	// +optional
+	Period *metav1.Duration `json:"period,omitempty"`
+	// Burst is the number of requests a source may send at once above the average. Defaults to 1
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	Burst *int64 `json:"burst,omitempty"`
+	// Source defines how the requests are grouped into sources. Defaults to the client address
+	// +optional
+	Source *RingRateLimitSource `json:"source,omitempty"`
+}
+
+type RingMiddleware struct {
+	// Name of the Middleware. With a spec the Middleware is created as <ring name>-<name>,
+	// otherwise it references an existing Middleware
+	Name string `json:"name"`
+	// Namespace of the referenced Middleware, defaults to the namespace of the ring. Unused with a spec
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+	// Spec of the Middleware created and owned by the ring, exactly one middleware may be set
+	// It is kept as written and read by the router, which creates the Middleware in its own format
+	// +optional
+	// +kubebuilder:pruning:PreserveUnknownFields
+	Spec *runtime.RawExtension `json:"spec,omitempty"`
+}
+
 type RingRouting struct {
 	// The target group of the ring
-	Group RingGroup `json:"group"`
+	// Deprecated: use Groups, the group is kept for the rings created before Groups existed
+	// +optional
+	Group RingGroup `json:"group,omitempty"`
+	// The target groups of the ring, a request from a member of any of the groups is routed to the ring
+	// +optional
+	Groups []RingGroup `json:"groups,omitempty"`
+	// Matchers route the requests they match to the ring in addition to the members of its groups
+	// (eg: an office network or a preview cookie for clients which cannot set the group header)
+	// +optional
+	Matchers []RingMatcher `json:"matchers,omitempty"`
+	// Hosts restrict the ring to requests for these hostnames, a leading "*." matches any subdomain
+	// When empty the ring is routed on every host
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+	// EntryPoints are the Traefik entrypoints the ring is exposed on (eg: internal for rings kept off the public entrypoints)
+	// Defaults to the entrypoints of the operator
+	// +optional
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	// TLS terminates HTTPS for the ring with the given certificate
+	// +optional
+	TLS *RingTLS `json:"tls,omitempty"`
+	// StampHeaders adds X-Ring-Name, X-Ring-Version and X-Ring-Branch to the requests routed to the ring
+	// and to their responses. Defaults to the RING_STAMP_HEADERS setting of the operator
+	// +optional
+	StampHeaders *bool `json:"stampHeaders,omitempty"`
+	// RequireReadyEndpoints only routes the ring once every branch it targets has ready endpoints, the requests
+	// of the ring fall back to the production ring meanwhile. Defaults to the RING_REQUIRE_READY_ENDPOINTS setting of the operator
+	// +optional
+	RequireReadyEndpoints *bool `json:"requireReadyEndpoints,omitempty"`
+	// RateLimit limits the rate at which every source may send requests to the ring
+	// +optional
+	RateLimit *RingRateLimit `json:"rateLimit,omitempty"`
+	// Middlewares are applied in order to the requests routed to the ring, after the rate limit and
+	// before the path is rewritten
+	// +optional
+	Middlewares []RingMiddleware `json:"middlewares,omitempty"`
+	// Path configures the path prefix the ring is routed on and how it is rewritten
+	// Defaults to /{service}/{version}, stripped from the request
+	// +optional
+	Path *RingPath `json:"path,omitempty"`
 	// Service will target the deployments with this service tag
 	Service string `json:"service"`
 	// Version will target the deployments with this major version tag
@@ -54,23 +252,226 @@ type RingRouting struct {
 	Branch string `json:"branch"`
 	// Ports will expose these ports on the services and verified against the Deployment found
 	Ports []RingPort `json:"ports"`
+	// Split divides the traffic of the ring by weight across the deployments of several branches
+	// When empty all the traffic of the ring goes to Branch
+	// +optional
+	Split []RingBranchWeight `json:"split,omitempty"`
+}
+
+type RingRolloutStep struct {
+	// Weight is the share of the ring traffic, out of 100, sent to the rollout branch during the step
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	Weight *int `json:"weight,omitempty"`
+	// Group replaces the target groups of the ring during the step
+	// +optional
+	Group string `json:"group,omitempty"`
+	// Bake is how long the step is held before the rollout moves to the next step (eg: 30m)
+	Bake metav1.Duration `json:"bake"`
+}
+
+type RingAnalysis struct {
+	// Interval between two analyses while a step bakes, defaults to 1m
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+	// Window is the range of the metric queries, defaults to 5m
+	// +optional
+	Window *metav1.Duration `json:"window,omitempty"`
+	// MinSuccessRate is the lowest share of requests, between 0 and 1, which must not fail with a server error
+	// +optional
+	MinSuccessRate *float64 `json:"minSuccessRate,omitempty"`
+	// MaxLatency is the highest 99th percentile latency accepted
+	// +optional
+	MaxLatency *metav1.Duration `json:"maxLatency,omitempty"`
+}
+
+type RingRollout struct {
+	// Branch is the branch rolled out by the weight steps, the ring branch receives the remaining traffic
+	// +optional
+	Branch string `json:"branch,omitempty"`
+	// Steps are run in order, each one sets either a weight or a group
+	Steps []RingRolloutStep `json:"steps"`
+	// Analysis checks the metrics of the rolled out branch before each step and rolls the ring back
+	// when a threshold is breached
+	// +optional
+	Analysis *RingAnalysis `json:"analysis,omitempty"`
 }
 
+// RingState is the state a Ring is reconciled to
+type RingState string
+
+const (
+	// RingActive means the ring is routed
+	RingActive RingState = "Active"
+	// RingStandby means the Service of the ring is kept but the ring is not routed
+	RingStandby RingState = "Standby"
+	// RingDisabled means every child of the ring is removed
+	RingDisabled RingState = "Disabled"
+)
+
 // RingSpec defines the desired state of Ring
 // +k8s:openapi-gen=true
 type RingSpec struct {
 	// Deploy marks whether this ring will be deployed to the live environment
+	// A ring which is not deployed is on standby unless its state is Disabled
 	Deploy bool `json:"deploy"`
+	// State of the ring, one of Active, Standby or Disabled. Defaults to Active
+	// +kubebuilder:validation:Enum=Active,Standby,Disabled
+	// +optional
+	State RingState `json:"state,omitempty"`
 	// Routing describes the service, group and users to be included in the ring
 	Routing RingRouting `json:"routing"`
+	// Rollout progressively changes the routing of the ring through a schedule of steps
+	// +optional
+	Rollout *RingRollout `json:"rollout,omitempty"`
+}
+
+// RingConditionType is the type of a condition reported on a Ring
+type RingConditionType string
+
+const (
+	// RingReady is true when the ring has been fully reconciled and its route is live
+	RingReady RingConditionType = "Ready"
+	// RingRoutingConfigured is true when the Service, IngressRoute and Middleware of the ring are up to date
+	RingRoutingConfigured RingConditionType = "RoutingConfigured"
+	// RingIdentityReady is true when the AAD group backing the ring exists or is not required
+	RingIdentityReady RingConditionType = "IdentityReady"
+	// RingDegraded is true when the last reconciliation of the ring failed
+	RingDegraded RingConditionType = "Degraded"
+	// RingCertificateReady is true once the cert-manager Certificate of the ring has been issued
+	RingCertificateReady RingConditionType = "CertificateReady"
+	// RingWorkloadVerified is true when a Deployment exposing every port of the ring is found for each of its branches
+	RingWorkloadVerified RingConditionType = "WorkloadVerified"
+	// RingEndpointsReady is true when every branch of a ring requiring ready endpoints has ready endpoints
+	RingEndpointsReady RingConditionType = "EndpointsReady"
+	// RingRouteAccepted is true once the ingress controller accepted the route of the ring, it is only
+	// reported by the routers whose routes have a status
+	RingRouteAccepted RingConditionType = "RouteAccepted"
+	// RingAnalysisReady is true when the metrics provider answered the last analysis of the rollout of the ring,
+	// a rollout whose analysis can't query its metrics holds its step
+	RingAnalysisReady RingConditionType = "AnalysisReady"
+)
+
+// RingCondition describes one aspect of the observed state of a Ring
+type RingCondition struct {
+	// Type of the condition
+	Type RingConditionType `json:"type"`
+	// Status of the condition, one of True, False or Unknown
+	Status corev1.ConditionStatus `json:"status"`
+	// LastTransitionTime is the last time the condition changed from one status to another
+	// +optional
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+	// Reason is a one-word CamelCase reason for the last transition
+	// +optional
+	Reason string `json:"reason,omitempty"`
+	// Message is a human readable description of the last transition
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// RingRolloutPhase is the phase of the rollout of a Ring
+type RingRolloutPhase string
+
+const (
+	// RolloutProgressing means the rollout moves to the next step once the current one has baked
+	RolloutProgressing RingRolloutPhase = "Progressing"
+	// RolloutPaused means the rollout holds the current step until it is resumed
+	RolloutPaused RingRolloutPhase = "Paused"
+	// RolloutAborted means the ring is routed as if it had no rollout
+	RolloutAborted RingRolloutPhase = "Aborted"
+	// RolloutCompleted means the last step has baked and its routing is kept
+	RolloutCompleted RingRolloutPhase = "Completed"
+	// RolloutRolledBack means the analysis breached a threshold and the ring is routed as if it had no rollout
+	RolloutRolledBack RingRolloutPhase = "RolledBack"
+)
+
+// RingAnalysisResult is the outcome of an analysis of a Ring
+type RingAnalysisResult string
+
+const (
+	// AnalysisPassed means every threshold was met
+	AnalysisPassed RingAnalysisResult = "Passed"
+	// AnalysisFailed means at least one threshold was breached
+	AnalysisFailed RingAnalysisResult = "Failed"
+	// AnalysisInconclusive means the metrics had no data, the rollout holds its step
+	AnalysisInconclusive RingAnalysisResult = "Inconclusive"
+)
+
+type RingAnalysisStatus struct {
+	// Result of the last analysis
+	Result RingAnalysisResult `json:"result"`
+	// Time of the last analysis
+	Time metav1.Time `json:"time"`
+	// Branch is the branch whose metrics were analysed
+	Branch string `json:"branch"`
+	// SuccessRate measured by the last analysis
+	// +optional
+	SuccessRate *float64 `json:"successRate,omitempty"`
+	// Latency is the 99th percentile latency measured by the last analysis
+	// +optional
+	Latency *metav1.Duration `json:"latency,omitempty"`
+	// Message explains the result of the last analysis
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+type RingRolloutStatus struct {
+	// Phase of the rollout
+	Phase RingRolloutPhase `json:"phase"`
+	// Step is the index of the current rollout step
+	Step int `json:"step"`
+	// SpecHash identifies the branch and steps the rollout was started with, the rollout starts over when
+	// they change
+	// +optional
+	SpecHash string `json:"specHash,omitempty"`
+	// StartTime is when the rollout started
+	// +optional
+	StartTime *metav1.Time `json:"startTime,omitempty"`
+	// StepStartTime is when the current step started baking
+	// +optional
+	StepStartTime *metav1.Time `json:"stepStartTime,omitempty"`
+	// CompletionTime is when the rollout completed, was aborted or rolled back
+	// +optional
+	CompletionTime *metav1.Time `json:"completionTime,omitempty"`
+	// Analysis is the result of the last analysis of the rollout
+	// +optional
+	Analysis *RingAnalysisStatus `json:"analysis,omitempty"`
 }
 
 // RingStatus defines the observed state of Ring
 // +k8s:openapi-gen=true
 type RingStatus struct {
-	// INSERT ADDITIONAL STATUS FIELD - define observed state of cluster
-	// Important: Run "operator-sdk generate k8s" to regenerate code after modifying this file
-	// Add custom validation using kubebuilder tags: https://book.kubebuilder.io/beyond_basics/generating_crd.html
+	// ObservedGeneration is the most recent generation of the Ring reconciled by the operator
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// State the ring was last reconciled to
+	// +optional
+	State RingState `json:"state,omitempty"`
+	// Conditions describe the current state of the ring
+	// +optional
+	Conditions []RingCondition `json:"conditions,omitempty"`
+	// ServiceName is the name of the Service created for the ring
+	// +optional
+	ServiceName string `json:"serviceName,omitempty"`
+	// IngressRouteName is the name of the route created for the ring (eg: IngressRoute or HTTPRoute)
+	// +optional
+	IngressRouteName string `json:"ingressRouteName,omitempty"`
+	// MiddlewareNames are the names of the Middlewares created for the ring
+	// +optional
+	MiddlewareNames []string `json:"middlewareNames,omitempty"`
+	// CertificateName is the name of the cert-manager Certificate created for the ring
+	// +optional
+	CertificateName string `json:"certificateName,omitempty"`
+	// Match is the Traefik rule rendered for the ring, empty with the other routers
+	// +optional
+	Match string `json:"match,omitempty"`
+	// LastError is the error returned by the last failed reconciliation, empty once it succeeds
+	// +optional
+	LastError string `json:"lastError,omitempty"`
+	// Rollout is the progress of the rollout of the ring
+	// +optional
+	Rollout *RingRolloutStatus `json:"rollout,omitempty"`
 }
 
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
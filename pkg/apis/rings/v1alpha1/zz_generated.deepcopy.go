@@ -5,6 +5,7 @@
 package v1alpha1
 
 import (
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -14,7 +15,7 @@ func (in *Ring) DeepCopyInto(out *Ring) {
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
 	in.Spec.DeepCopyInto(&out.Spec)
-	out.Status = in.Status
+	in.Status.DeepCopyInto(&out.Status)
 	return
 }
 
@@ -36,6 +37,118 @@ func (in *Ring) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingAnalysis) DeepCopyInto(out *RingAnalysis) {
+	*out = *in
+	if in.Interval != nil {
+		in, out := &in.Interval, &out.Interval
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Window != nil {
+		in, out := &in.Window, &out.Window
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.MinSuccessRate != nil {
+		in, out := &in.MinSuccessRate, &out.MinSuccessRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.MaxLatency != nil {
+		in, out := &in.MaxLatency, &out.MaxLatency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingAnalysis.
+func (in *RingAnalysis) DeepCopy() *RingAnalysis {
+	if in == nil {
+		return nil
+	}
+	out := new(RingAnalysis)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingAnalysisStatus) DeepCopyInto(out *RingAnalysisStatus) {
+	*out = *in
+	in.Time.DeepCopyInto(&out.Time)
+	if in.SuccessRate != nil {
+		in, out := &in.SuccessRate, &out.SuccessRate
+		*out = new(float64)
+		**out = **in
+	}
+	if in.Latency != nil {
+		in, out := &in.Latency, &out.Latency
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingAnalysisStatus.
+func (in *RingAnalysisStatus) DeepCopy() *RingAnalysisStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RingAnalysisStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingBranchWeight) DeepCopyInto(out *RingBranchWeight) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingBranchWeight.
+func (in *RingBranchWeight) DeepCopy() *RingBranchWeight {
+	if in == nil {
+		return nil
+	}
+	out := new(RingBranchWeight)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingCertificate) DeepCopyInto(out *RingCertificate) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingCertificate.
+func (in *RingCertificate) DeepCopy() *RingCertificate {
+	if in == nil {
+		return nil
+	}
+	out := new(RingCertificate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingCondition) DeepCopyInto(out *RingCondition) {
+	*out = *in
+	in.LastTransitionTime.DeepCopyInto(&out.LastTransitionTime)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingCondition.
+func (in *RingCondition) DeepCopy() *RingCondition {
+	if in == nil {
+		return nil
+	}
+	out := new(RingCondition)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RingGroup) DeepCopyInto(out *RingGroup) {
 	*out = *in
@@ -90,6 +203,59 @@ func (in *RingList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingMatcher) DeepCopyInto(out *RingMatcher) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingMatcher.
+func (in *RingMatcher) DeepCopy() *RingMatcher {
+	if in == nil {
+		return nil
+	}
+	out := new(RingMatcher)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingMiddleware) DeepCopyInto(out *RingMiddleware) {
+	*out = *in
+	if in.Spec != nil {
+		in, out := &in.Spec, &out.Spec
+		*out = new(runtime.RawExtension)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingMiddleware.
+func (in *RingMiddleware) DeepCopy() *RingMiddleware {
+	if in == nil {
+		return nil
+	}
+	out := new(RingMiddleware)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingPath) DeepCopyInto(out *RingPath) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingPath.
+func (in *RingPath) DeepCopy() *RingPath {
+	if in == nil {
+		return nil
+	}
+	out := new(RingPath)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RingPort) DeepCopyInto(out *RingPort) {
 	*out = *in
@@ -107,15 +273,209 @@ func (in *RingPort) DeepCopy() *RingPort {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingRateLimit) DeepCopyInto(out *RingRateLimit) {
+	*out = *in
+	if in.Period != nil {
+		in, out := &in.Period, &out.Period
+		*out = new(v1.Duration)
+		**out = **in
+	}
+	if in.Burst != nil {
+		in, out := &in.Burst, &out.Burst
+		*out = new(int64)
+		**out = **in
+	}
+	if in.Source != nil {
+		in, out := &in.Source, &out.Source
+		*out = new(RingRateLimitSource)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingRateLimit.
+func (in *RingRateLimit) DeepCopy() *RingRateLimit {
+	if in == nil {
+		return nil
+	}
+	out := new(RingRateLimit)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingRateLimitSource) DeepCopyInto(out *RingRateLimitSource) {
+	*out = *in
+	if in.ExcludedIPs != nil {
+		in, out := &in.ExcludedIPs, &out.ExcludedIPs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingRateLimitSource.
+func (in *RingRateLimitSource) DeepCopy() *RingRateLimitSource {
+	if in == nil {
+		return nil
+	}
+	out := new(RingRateLimitSource)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingRollout) DeepCopyInto(out *RingRollout) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]RingRolloutStep, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(RingAnalysis)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingRollout.
+func (in *RingRollout) DeepCopy() *RingRollout {
+	if in == nil {
+		return nil
+	}
+	out := new(RingRollout)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingRolloutStatus) DeepCopyInto(out *RingRolloutStatus) {
+	*out = *in
+	if in.StartTime != nil {
+		in, out := &in.StartTime, &out.StartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.StepStartTime != nil {
+		in, out := &in.StepStartTime, &out.StepStartTime
+		*out = (*in).DeepCopy()
+	}
+	if in.CompletionTime != nil {
+		in, out := &in.CompletionTime, &out.CompletionTime
+		*out = (*in).DeepCopy()
+	}
+	if in.Analysis != nil {
+		in, out := &in.Analysis, &out.Analysis
+		*out = new(RingAnalysisStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingRolloutStatus.
+func (in *RingRolloutStatus) DeepCopy() *RingRolloutStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RingRolloutStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingRolloutStep) DeepCopyInto(out *RingRolloutStep) {
+	*out = *in
+	if in.Weight != nil {
+		in, out := &in.Weight, &out.Weight
+		*out = new(int)
+		**out = **in
+	}
+	out.Bake = in.Bake
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingRolloutStep.
+func (in *RingRolloutStep) DeepCopy() *RingRolloutStep {
+	if in == nil {
+		return nil
+	}
+	out := new(RingRolloutStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RingRouting) DeepCopyInto(out *RingRouting) {
 	*out = *in
 	in.Group.DeepCopyInto(&out.Group)
+	if in.Groups != nil {
+		in, out := &in.Groups, &out.Groups
+		*out = make([]RingGroup, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Matchers != nil {
+		in, out := &in.Matchers, &out.Matchers
+		*out = make([]RingMatcher, len(*in))
+		copy(*out, *in)
+	}
+	if in.Hosts != nil {
+		in, out := &in.Hosts, &out.Hosts
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.EntryPoints != nil {
+		in, out := &in.EntryPoints, &out.EntryPoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(RingTLS)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.StampHeaders != nil {
+		in, out := &in.StampHeaders, &out.StampHeaders
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RequireReadyEndpoints != nil {
+		in, out := &in.RequireReadyEndpoints, &out.RequireReadyEndpoints
+		*out = new(bool)
+		**out = **in
+	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(RingRateLimit)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Middlewares != nil {
+		in, out := &in.Middlewares, &out.Middlewares
+		*out = make([]RingMiddleware, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Path != nil {
+		in, out := &in.Path, &out.Path
+		*out = new(RingPath)
+		**out = **in
+	}
 	if in.Ports != nil {
 		in, out := &in.Ports, &out.Ports
 		*out = make([]RingPort, len(*in))
 		copy(*out, *in)
 	}
+	if in.Split != nil {
+		in, out := &in.Split, &out.Split
+		*out = make([]RingBranchWeight, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -133,6 +493,11 @@ func (in *RingRouting) DeepCopy() *RingRouting {
 func (in *RingSpec) DeepCopyInto(out *RingSpec) {
 	*out = *in
 	in.Routing.DeepCopyInto(&out.Routing)
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RingRollout)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -149,6 +514,23 @@ func (in *RingSpec) DeepCopy() *RingSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RingStatus) DeepCopyInto(out *RingStatus) {
 	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]RingCondition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.MiddlewareNames != nil {
+		in, out := &in.MiddlewareNames, &out.MiddlewareNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Rollout != nil {
+		in, out := &in.Rollout, &out.Rollout
+		*out = new(RingRolloutStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -161,3 +543,73 @@ func (in *RingStatus) DeepCopy() *RingStatus {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingTLS) DeepCopyInto(out *RingTLS) {
+	*out = *in
+	if in.Options != nil {
+		in, out := &in.Options, &out.Options
+		*out = new(RingTLSOptionRef)
+		**out = **in
+	}
+	if in.Domains != nil {
+		in, out := &in.Domains, &out.Domains
+		*out = make([]RingTLSDomain, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Certificate != nil {
+		in, out := &in.Certificate, &out.Certificate
+		*out = new(RingCertificate)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingTLS.
+func (in *RingTLS) DeepCopy() *RingTLS {
+	if in == nil {
+		return nil
+	}
+	out := new(RingTLS)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingTLSDomain) DeepCopyInto(out *RingTLSDomain) {
+	*out = *in
+	if in.SANs != nil {
+		in, out := &in.SANs, &out.SANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingTLSDomain.
+func (in *RingTLSDomain) DeepCopy() *RingTLSDomain {
+	if in == nil {
+		return nil
+	}
+	out := new(RingTLSDomain)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RingTLSOptionRef) DeepCopyInto(out *RingTLSOptionRef) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RingTLSOptionRef.
+func (in *RingTLSOptionRef) DeepCopy() *RingTLSOptionRef {
+	if in == nil {
+		return nil
+	}
+	out := new(RingTLSOptionRef)
+	in.DeepCopyInto(out)
+	return out
+}
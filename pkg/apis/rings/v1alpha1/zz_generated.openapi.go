@@ -70,23 +70,36 @@ func schema_pkg_apis_rings_v1alpha1_RingSpec(ref common.ReferenceCallback) commo
 				Properties: map[string]spec.Schema{
 					"deploy": {
 						SchemaProps: spec.SchemaProps{
-							Description: "Deploy marks whether this ring will be deployed to the live environment",
+							Description: "Deploy marks whether this ring will be deployed to the live environment A ring which is not deployed is on standby unless its state is Disabled",
 							Type:        []string{"boolean"},
 							Format:      "",
 						},
 					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State of the ring, one of Active, Standby or Disabled. Defaults to Active",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
 					"routing": {
 						SchemaProps: spec.SchemaProps{
 							Description: "Routing describes the service, group and users to be included in the ring",
 							Ref:         ref("ring-operator/pkg/apis/rings/v1alpha1.RingRouting"),
 						},
 					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rollout progressively changes the routing of the ring through a schedule of steps",
+							Ref:         ref("ring-operator/pkg/apis/rings/v1alpha1.RingRollout"),
+						},
+					},
 				},
 				Required: []string{"deploy", "routing"},
 			},
 		},
 		Dependencies: []string{
-			"ring-operator/pkg/apis/rings/v1alpha1.RingRouting"},
+			"ring-operator/pkg/apis/rings/v1alpha1.RingRollout", "ring-operator/pkg/apis/rings/v1alpha1.RingRouting"},
 	}
 }
 
@@ -95,9 +108,93 @@ func schema_pkg_apis_rings_v1alpha1_RingStatus(ref common.ReferenceCallback) com
 		Schema: spec.Schema{
 			SchemaProps: spec.SchemaProps{
 				Description: "RingStatus defines the observed state of Ring",
-				Properties:  map[string]spec.Schema{},
+				Properties: map[string]spec.Schema{
+					"observedGeneration": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ObservedGeneration is the most recent generation of the Ring reconciled by the operator",
+							Type:        []string{"integer"},
+							Format:      "int64",
+						},
+					},
+					"state": {
+						SchemaProps: spec.SchemaProps{
+							Description: "State the ring was last reconciled to",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"conditions": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Conditions describe the current state of the ring",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Ref: ref("ring-operator/pkg/apis/rings/v1alpha1.RingCondition"),
+									},
+								},
+							},
+						},
+					},
+					"serviceName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "ServiceName is the name of the Service created for the ring",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"ingressRouteName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "IngressRouteName is the name of the route created for the ring (eg: IngressRoute or HTTPRoute)",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"middlewareNames": {
+						SchemaProps: spec.SchemaProps{
+							Description: "MiddlewareNames are the names of the Middlewares created for the ring",
+							Type:        []string{"array"},
+							Items: &spec.SchemaOrArray{
+								Schema: &spec.Schema{
+									SchemaProps: spec.SchemaProps{
+										Type:   []string{"string"},
+										Format: "",
+									},
+								},
+							},
+						},
+					},
+					"certificateName": {
+						SchemaProps: spec.SchemaProps{
+							Description: "CertificateName is the name of the cert-manager Certificate created for the ring",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"match": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Match is the Traefik rule rendered for the ring, empty with the other routers",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"lastError": {
+						SchemaProps: spec.SchemaProps{
+							Description: "LastError is the error returned by the last failed reconciliation, empty once it succeeds",
+							Type:        []string{"string"},
+							Format:      "",
+						},
+					},
+					"rollout": {
+						SchemaProps: spec.SchemaProps{
+							Description: "Rollout is the progress of the rollout of the ring",
+							Ref:         ref("ring-operator/pkg/apis/rings/v1alpha1.RingRolloutStatus"),
+						},
+					},
+				},
 			},
 		},
-		Dependencies: []string{},
+		Dependencies: []string{
+			"ring-operator/pkg/apis/rings/v1alpha1.RingCondition", "ring-operator/pkg/apis/rings/v1alpha1.RingRolloutStatus"},
 	}
 }
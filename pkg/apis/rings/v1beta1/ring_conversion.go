@@ -0,0 +1,128 @@
+package v1beta1
+
+import (
+	"encoding/json"
+
+	"github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+)
+
+// LegacyGroupAnnotation keeps the deprecated v1alpha1 group of a ring converted to v1beta1
+// The group leads the v1beta1 identity groups so the ring is routed the same, the annotation tells
+// it apart from the groups list when the ring is converted back
+const LegacyGroupAnnotation = "rings.microsoft.com/v1alpha1-group"
+
+// ConvertTo converts the ring to the v1alpha1 hub version
+func (r *Ring) ConvertTo(hub *v1alpha1.Ring) error {
+	hub.TypeMeta = r.TypeMeta
+	hub.APIVersion = v1alpha1.SchemeGroupVersion.String()
+	hub.ObjectMeta = *r.ObjectMeta.DeepCopy()
+
+	groups := r.Spec.Identity.Groups
+	group := v1alpha1.RingGroup{}
+	if raw, ok := hub.Annotations[LegacyGroupAnnotation]; ok {
+		if err := json.Unmarshal([]byte(raw), &group); err != nil {
+			return err
+		}
+		// The group is only restored while it still leads the groups
+		if len(groups) > 0 && groups[0].Name == group.Name {
+			group, groups = groups[0], groups[1:]
+		} else {
+			group = v1alpha1.RingGroup{}
+		}
+		delete(hub.Annotations, LegacyGroupAnnotation)
+		if len(hub.Annotations) == 0 {
+			hub.Annotations = nil
+		}
+	}
+
+	spec := r.Spec.DeepCopy()
+	hub.Spec = v1alpha1.RingSpec{
+		Deploy: spec.Deploy,
+		State:  spec.State,
+		Routing: v1alpha1.RingRouting{
+			Group:                 *group.DeepCopy(),
+			Groups:                copyGroups(groups),
+			Matchers:              spec.Match.Matchers,
+			Hosts:                 spec.Match.Hosts,
+			EntryPoints:           spec.Match.EntryPoints,
+			TLS:                   spec.Match.TLS,
+			StampHeaders:          spec.Backend.StampHeaders,
+			RequireReadyEndpoints: spec.Backend.RequireReadyEndpoints,
+			RateLimit:             spec.Backend.RateLimit,
+			Middlewares:           spec.Backend.Middlewares,
+			Path:                  spec.Match.Path,
+			Service:               spec.Backend.Service,
+			Version:               spec.Backend.Version,
+			Branch:                spec.Backend.Branch,
+			Ports:                 spec.Backend.Ports,
+			Split:                 spec.Backend.Split,
+		},
+		Rollout: spec.Rollout,
+	}
+	r.Status.DeepCopyInto(&hub.Status)
+	return nil
+}
+
+// ConvertFrom converts the ring from the v1alpha1 hub version
+func (r *Ring) ConvertFrom(hub *v1alpha1.Ring) error {
+	r.TypeMeta = hub.TypeMeta
+	r.APIVersion = SchemeGroupVersion.String()
+	r.ObjectMeta = *hub.ObjectMeta.DeepCopy()
+
+	spec := hub.Spec.DeepCopy()
+	routing := &spec.Routing
+
+	groups := routing.Groups
+	if routing.Group.Name != "" {
+		raw, err := json.Marshal(routing.Group)
+		if err != nil {
+			return err
+		}
+		if r.Annotations == nil {
+			r.Annotations = map[string]string{}
+		}
+		r.Annotations[LegacyGroupAnnotation] = string(raw)
+		groups = append([]v1alpha1.RingGroup{routing.Group}, groups...)
+	}
+
+	r.Spec = RingSpec{
+		Deploy: spec.Deploy,
+		State:  spec.State,
+		Match: RingMatch{
+			Hosts:       routing.Hosts,
+			Path:        routing.Path,
+			Matchers:    routing.Matchers,
+			EntryPoints: routing.EntryPoints,
+			TLS:         routing.TLS,
+		},
+		Backend: RingBackend{
+			Service:               routing.Service,
+			Version:               routing.Version,
+			Branch:                routing.Branch,
+			Ports:                 routing.Ports,
+			Split:                 routing.Split,
+			Middlewares:           routing.Middlewares,
+			RateLimit:             routing.RateLimit,
+			StampHeaders:          routing.StampHeaders,
+			RequireReadyEndpoints: routing.RequireReadyEndpoints,
+		},
+		Identity: RingIdentity{
+			Groups: groups,
+		},
+		Rollout: spec.Rollout,
+	}
+	hub.Status.DeepCopyInto(&r.Status)
+	return nil
+}
+
+// copyGroups returns a deep copy of the groups, nil when there are none
+func copyGroups(groups []v1alpha1.RingGroup) []v1alpha1.RingGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+	copied := make([]v1alpha1.RingGroup, len(groups))
+	for i := range groups {
+		groups[i].DeepCopyInto(&copied[i])
+	}
+	return copied
+}
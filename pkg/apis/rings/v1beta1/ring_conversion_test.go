@@ -0,0 +1,100 @@
+package v1beta1_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+	"github.com/microsoft/ring-operator/pkg/apis/rings/v1beta1"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+func createHubRing() *v1alpha1.Ring {
+	stamp := true
+	weight := 10
+	return &v1alpha1.Ring{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: v1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Ring",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        "query-v1-canary",
+			Namespace:   "default",
+			Labels:      map[string]string{"branch": "canary"},
+			Annotations: map[string]string{"owner": "search"},
+		},
+		Spec: v1alpha1.RingSpec{
+			Deploy: true,
+			Routing: v1alpha1.RingRouting{
+				Group:                 v1alpha1.RingGroup{Name: "canary", InitialUsers: []string{"jane@example.com"}},
+				Groups:                []v1alpha1.RingGroup{{Name: "dogfood"}},
+				Matchers:              []v1alpha1.RingMatcher{{Type: v1alpha1.MatchCookie, Name: "preview", Value: "true"}},
+				Hosts:                 []string{"*.example.com"},
+				EntryPoints:           []string{"internal"},
+				TLS:                   &v1alpha1.RingTLS{SecretName: "example-tls"},
+				StampHeaders:          &stamp,
+				RequireReadyEndpoints: &stamp,
+				RateLimit:             &v1alpha1.RingRateLimit{Average: 100},
+				Middlewares: []v1alpha1.RingMiddleware{
+					{Name: "compress", Spec: &runtime.RawExtension{Raw: []byte(`{"compress":{}}`)}},
+				},
+				Path:    &v1alpha1.RingPath{Template: "/{service}", Rewrite: v1alpha1.RewriteKeep},
+				Service: "query",
+				Version: "v1",
+				Branch:  "canary",
+				Ports: []v1alpha1.RingPort{
+					{Name: "http", Protocol: corev1.ProtocolTCP, Port: 80, TargetPort: intstr.FromString("web")},
+				},
+				Split: []v1alpha1.RingBranchWeight{{Branch: "canary", Weight: 90}, {Branch: "beta", Weight: 10}},
+			},
+			Rollout: &v1alpha1.RingRollout{
+				Branch: "beta",
+				Steps:  []v1alpha1.RingRolloutStep{{Weight: &weight, Bake: metav1.Duration{Duration: time.Minute}}},
+			},
+		},
+		Status: v1alpha1.RingStatus{
+			ObservedGeneration: 2,
+			Conditions: []v1alpha1.RingCondition{
+				{Type: v1alpha1.RingReady, Status: corev1.ConditionTrue, Reason: "Reconciled"},
+			},
+			ServiceName: "query-v1-canary",
+		},
+	}
+}
+
+func TestConvertRoundTrip(t *testing.T) {
+	// v1alpha1 -> v1beta1 -> v1alpha1
+	hub := createHubRing()
+	ring := &v1beta1.Ring{}
+	require.NoError(t, ring.ConvertFrom(hub))
+	require.Equal(t, v1beta1.SchemeGroupVersion.String(), ring.APIVersion)
+	require.Equal(t, "query", ring.Spec.Backend.Service)
+	require.Equal(t, []string{"*.example.com"}, ring.Spec.Match.Hosts)
+	require.Equal(t, []string{"canary", "dogfood"}, []string{ring.Spec.Identity.Groups[0].Name, ring.Spec.Identity.Groups[1].Name})
+	require.Contains(t, ring.Annotations, v1beta1.LegacyGroupAnnotation)
+
+	converted := &v1alpha1.Ring{}
+	require.NoError(t, ring.ConvertTo(converted))
+	require.Equal(t, hub, converted)
+
+	// v1beta1 -> v1alpha1 -> v1beta1
+	ring = &v1beta1.Ring{}
+	require.NoError(t, ring.ConvertFrom(createHubRing()))
+	ring.Annotations = map[string]string{"owner": "search"}
+	ring.Spec.Identity.Groups = ring.Spec.Identity.Groups[1:]
+
+	hub = &v1alpha1.Ring{}
+	require.NoError(t, ring.ConvertTo(hub))
+	// Rings created as v1beta1 have no legacy group
+	require.Empty(t, hub.Spec.Routing.Group.Name)
+	require.Equal(t, []v1alpha1.RingGroup{{Name: "dogfood"}}, hub.Spec.Routing.Groups)
+
+	roundTripped := &v1beta1.Ring{}
+	require.NoError(t, roundTripped.ConvertFrom(hub))
+	require.Equal(t, ring, roundTripped)
+}
@@ -0,0 +1,117 @@
+package v1beta1
+
+import (
+	"github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// RingMatch describes the requests routed to the ring
+type RingMatch struct {
+	// Hosts restrict the ring to requests for these hostnames, a leading "*." matches any subdomain
+	// When empty the ring is routed on every host
+	// +optional
+	Hosts []string `json:"hosts,omitempty"`
+	// Path configures the path prefix the ring is routed on and how it is rewritten
+	// Defaults to /{service}/{version}, stripped from the request
+	// +optional
+	Path *v1alpha1.RingPath `json:"path,omitempty"`
+	// Matchers route the requests they match to the ring in addition to the members of its groups
+	// (eg: an office network or a preview cookie for clients which cannot set the group header)
+	// +optional
+	Matchers []v1alpha1.RingMatcher `json:"matchers,omitempty"`
+	// EntryPoints are the Traefik entrypoints the ring is exposed on (eg: internal for rings kept off the public entrypoints)
+	// Defaults to the entrypoints of the operator
+	// +optional
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	// TLS terminates HTTPS for the ring with the given certificate
+	// +optional
+	TLS *v1alpha1.RingTLS `json:"tls,omitempty"`
+}
+
+// RingBackend describes the deployments the requests of the ring are sent to and how they get there
+type RingBackend struct {
+	// Service will target the deployments with this service tag
+	Service string `json:"service"`
+	// Version will target the deployments with this major version tag
+	Version string `json:"version"`
+	// Branch will target the deployments with this branch tag
+	Branch string `json:"branch"`
+	// Ports will expose these ports on the services and verified against the Deployment found
+	Ports []v1alpha1.RingPort `json:"ports"`
+	// Split divides the traffic of the ring by weight across the deployments of several branches
+	// When empty all the traffic of the ring goes to Branch
+	// +optional
+	Split []v1alpha1.RingBranchWeight `json:"split,omitempty"`
+	// Middlewares are applied in order to the requests routed to the ring, after the rate limit and
+	// before the path is rewritten
+	// +optional
+	Middlewares []v1alpha1.RingMiddleware `json:"middlewares,omitempty"`
+	// RateLimit limits the rate at which every source may send requests to the ring
+	// +optional
+	RateLimit *v1alpha1.RingRateLimit `json:"rateLimit,omitempty"`
+	// StampHeaders adds X-Ring-Name, X-Ring-Version and X-Ring-Branch to the requests routed to the ring
+	// and to their responses. Defaults to the RING_STAMP_HEADERS setting of the operator
+	// +optional
+	StampHeaders *bool `json:"stampHeaders,omitempty"`
+	// RequireReadyEndpoints only routes the ring once every branch it targets has ready endpoints, the requests
+	// of the ring fall back to the production ring meanwhile. Defaults to the RING_REQUIRE_READY_ENDPOINTS setting of the operator
+	// +optional
+	RequireReadyEndpoints *bool `json:"requireReadyEndpoints,omitempty"`
+}
+
+// RingIdentity describes the users the ring is routed to
+type RingIdentity struct {
+	// Groups are the target groups of the ring, a request from a member of any of the groups is routed to the ring
+	// +optional
+	Groups []v1alpha1.RingGroup `json:"groups,omitempty"`
+}
+
+// RingSpec defines the desired state of Ring
+// +k8s:openapi-gen=true
+type RingSpec struct {
+	// Deploy marks whether this ring will be deployed to the live environment
+	// A ring which is not deployed is on standby unless its state is Disabled
+	Deploy bool `json:"deploy"`
+	// State of the ring, one of Active, Standby or Disabled. Defaults to Active
+	// +kubebuilder:validation:Enum=Active,Standby,Disabled
+	// +optional
+	State v1alpha1.RingState `json:"state,omitempty"`
+	// Match describes the requests routed to the ring
+	// +optional
+	Match RingMatch `json:"match,omitempty"`
+	// Backend describes the deployments the requests of the ring are sent to
+	Backend RingBackend `json:"backend"`
+	// Identity describes the users the ring is routed to
+	// +optional
+	Identity RingIdentity `json:"identity,omitempty"`
+	// Rollout progressively changes the routing of the ring through a schedule of steps
+	// +optional
+	Rollout *v1alpha1.RingRollout `json:"rollout,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Ring is the Schema for the rings API
+// +k8s:openapi-gen=true
+// +kubebuilder:subresource:status
+type Ring struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RingSpec            `json:"spec,omitempty"`
+	Status v1alpha1.RingStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// RingList contains a list of Ring
+type RingList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Ring `json:"items"`
+}
+
+func init() {
+	SchemeBuilder.Register(&Ring{}, &RingList{})
+}
@@ -0,0 +1,60 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// CertificateSpec is a specification for a Certificate resource.
+type CertificateSpec struct {
+	SecretName string          `json:"secretName"`
+	DNSNames   []string        `json:"dnsNames,omitempty"`
+	IssuerRef  ObjectReference `json:"issuerRef"`
+}
+
+// ObjectReference is a reference to the Issuer or ClusterIssuer signing the Certificate.
+type ObjectReference struct {
+	Name  string `json:"name"`
+	Kind  string `json:"kind,omitempty"`
+	Group string `json:"group,omitempty"`
+}
+
+// CertificateConditionType is the type of a CertificateCondition.
+type CertificateConditionType string
+
+// CertificateConditionReady is set once the Certificate has been issued and stored in its Secret.
+const CertificateConditionReady CertificateConditionType = "Ready"
+
+// CertificateCondition contains condition information for a Certificate.
+type CertificateCondition struct {
+	Type    CertificateConditionType `json:"type"`
+	Status  corev1.ConditionStatus   `json:"status"`
+	Reason  string                   `json:"reason,omitempty"`
+	Message string                   `json:"message,omitempty"`
+}
+
+// CertificateStatus is the status of a Certificate resource.
+type CertificateStatus struct {
+	Conditions []CertificateCondition `json:"conditions,omitempty"`
+	NotAfter   *metav1.Time           `json:"notAfter,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Certificate is a cert-manager Certificate CRD specification.
+type Certificate struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   CertificateSpec   `json:"spec"`
+	Status CertificateStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CertificateList is a list of Certificates.
+type CertificateList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Certificate `json:"items"`
+}
@@ -0,0 +1,6 @@
+// Package v1 contains the subset of the cert-manager CRD API (cert-manager.io/v1) that the ring
+// operator produces. The cert-manager module requires a far newer Kubernetes than the one pinned
+// in go.mod, so the types are kept here in the shape served by cert-manager v1.0+.
+// +k8s:deepcopy-gen=package
+// +groupName=cert-manager.io
+package v1
@@ -10,11 +10,10 @@ import (
 	"github.com/Azure/go-autorest/autorest/to"
 	"io/ioutil"
 	"os"
-	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
 )
 
 // CreateADGroup will create the AAD group in Azure
-func (r *ReconcileRing) createADGroup(cr *ringsv1alpha1.Ring) error {
+func (r *ReconcileRing) createADGroup(group string) error {
 	tenantId := os.Getenv("AZURE_TENANT_ID")
 	if tenantId == "" {
 		err := errors.New("could not read tenant from environment")
@@ -28,11 +27,11 @@ func (r *ReconcileRing) createADGroup(cr *ringsv1alpha1.Ring) error {
 		return err
 	}
 
-	r.logger.Info("Creating AAD Group", "Group", cr.Spec.Routing.Group.Name)
+	r.logger.Info("Creating AAD Group", "Group", group)
 	_, err = groupsClient.Create(context.TODO(), graphrbac.GroupCreateParameters{
-		DisplayName:     to.StringPtr(cr.Spec.Routing.Group.Name),
+		DisplayName:     to.StringPtr(group),
 		MailEnabled:     to.BoolPtr(false),
-		MailNickname:    to.StringPtr(cr.Spec.Routing.Group.Name),
+		MailNickname:    to.StringPtr(group),
 		SecurityEnabled: to.BoolPtr(true),
 	})
 
@@ -62,12 +61,12 @@ func (r *ReconcileRing) getGroupsClient() (*graphrbac.GroupsClient, error) {
 	return &client, nil
 }
 
-func (r *ReconcileRing) adGroupExists(cr *ringsv1alpha1.Ring) (bool, error) {
+func (r *ReconcileRing) adGroupExists(group string) (bool, error) {
 	// Check for production group
 	// In this case, production is the set of "ALL" users, not a specific group
 	// TODO - Add flag for making production an explicit group
-	if cr.Spec.Routing.Group.Name == "*" {
-		r.logger.Info("Listing requested for production group", "Group", cr.Spec.Routing.Group.Name)
+	if group == "*" {
+		r.logger.Info("Listing requested for production group", "Group", group)
 		return true, nil
 	}
 
@@ -77,12 +76,12 @@ func (r *ReconcileRing) adGroupExists(cr *ringsv1alpha1.Ring) (bool, error) {
 		return false, err
 	}
 
-	r.logger.Info("Listing AD Groups", "Group", cr.Spec.Routing.Group.Name)
+	r.logger.Info("Listing AD Groups", "Group", group)
 	//res, err := groupsClient.List(context.TODO(), "")
-	res, err := groupsClient.List(context.TODO(), fmt.Sprintf("mailNickname eq '%s'", cr.Spec.Routing.Group.Name))
+	res, err := groupsClient.List(context.TODO(), fmt.Sprintf("mailNickname eq '%s'", group))
 	if err != nil {
 		resStr, _ := ioutil.ReadAll(res.Response().Body)
-		r.logger.Error(err, "Could not list AD Groups", "Group", cr.Spec.Routing.Group.Name, "Reason", resStr)
+		r.logger.Error(err, "Could not list AD Groups", "Group", group, "Reason", resStr)
 		return false, err
 	}
 
@@ -90,14 +89,14 @@ func (r *ReconcileRing) adGroupExists(cr *ringsv1alpha1.Ring) (bool, error) {
 	return len(groups) > 0, nil
 }
 
-func (r *ReconcileRing) deleteADGroup(cr *ringsv1alpha1.Ring) error {
+func (r *ReconcileRing) deleteADGroup(group string) error {
 	groupsClient, err := r.getGroupsClient()
 	if err != nil {
 		r.logger.Error(err, "Could not init groups client")
 		return err
 	}
 
-	r.logger.Info("Deleting AAD Group", "Group", cr.Spec.Routing.Group)
+	r.logger.Info("Deleting AAD Group", "Group", group)
 	_, err = groupsClient.Delete(context.TODO(), "")
 
 	return err
@@ -0,0 +1,204 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+const (
+	// defaultSuccessRateQuery is the share of requests which did not fail with a server error
+	defaultSuccessRateQuery = `sum(rate(http_requests_total{ {{- .Selector -}} ,code!~"5.."}[{{ .Window }}])) / sum(rate(http_requests_total{ {{- .Selector -}} }[{{ .Window }}]))`
+	// defaultLatencyQuery is the 99th percentile request duration in seconds
+	defaultLatencyQuery = `histogram_quantile(0.99, sum(rate(http_request_duration_seconds_bucket{ {{- .Selector -}} }[{{ .Window }}])) by (le))`
+)
+
+// MetricsProvider queries the health of the deployments matching a ring selector
+// (eg: service, version and branch labels)
+// A nil value with a nil error means the provider had no data for the selector
+type MetricsProvider interface {
+	// SuccessRate returns the share of requests, between 0 and 1, which did not fail with a server error
+	SuccessRate(selector map[string]string, window time.Duration) (*float64, error)
+	// Latency returns the 99th percentile latency of the requests
+	Latency(selector map[string]string, window time.Duration) (*time.Duration, error)
+}
+
+// prometheusProvider implements MetricsProvider with instant queries against a Prometheus compatible HTTP API
+type prometheusProvider struct {
+	address          string
+	client           *http.Client
+	successRateQuery *template.Template
+	latencyQuery     *template.Template
+}
+
+// NewPrometheusProvider returns a MetricsProvider querying the Prometheus compatible HTTP API at address
+// with the default queries over the http_requests_total and http_request_duration_seconds metrics
+func NewPrometheusProvider(address string) (MetricsProvider, error) {
+	return newPrometheusProvider(address, defaultSuccessRateQuery, defaultLatencyQuery)
+}
+
+// newMetricsProviderFromEnv returns the metrics provider configured for the operator
+// It returns nil when RING_METRICS_ADDRESS is not set
+func newMetricsProviderFromEnv() (MetricsProvider, error) {
+	address := os.Getenv("RING_METRICS_ADDRESS")
+	if address == "" {
+		return nil, nil
+	}
+
+	successRateQuery := os.Getenv("RING_METRICS_SUCCESS_RATE_QUERY")
+	if successRateQuery == "" {
+		successRateQuery = defaultSuccessRateQuery
+	}
+
+	latencyQuery := os.Getenv("RING_METRICS_LATENCY_QUERY")
+	if latencyQuery == "" {
+		latencyQuery = defaultLatencyQuery
+	}
+
+	return newPrometheusProvider(address, successRateQuery, latencyQuery)
+}
+
+func newPrometheusProvider(address, successRateQuery, latencyQuery string) (*prometheusProvider, error) {
+	srq, err := template.New("successRate").Parse(successRateQuery)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse success rate query: %v", err)
+	}
+
+	lq, err := template.New("latency").Parse(latencyQuery)
+	if err != nil {
+		return nil, fmt.Errorf("could not parse latency query: %v", err)
+	}
+
+	return &prometheusProvider{
+		address:          strings.TrimSuffix(address, "/"),
+		client:           &http.Client{Timeout: 10 * time.Second},
+		successRateQuery: srq,
+		latencyQuery:     lq,
+	}, nil
+}
+
+func (p *prometheusProvider) SuccessRate(selector map[string]string, window time.Duration) (*float64, error) {
+	return p.query(p.successRateQuery, selector, window)
+}
+
+func (p *prometheusProvider) Latency(selector map[string]string, window time.Duration) (*time.Duration, error) {
+	seconds, err := p.query(p.latencyQuery, selector, window)
+	if err != nil || seconds == nil {
+		return nil, err
+	}
+
+	latency := time.Duration(*seconds * float64(time.Second))
+	return &latency, nil
+}
+
+// queryResponse is the subset of the Prometheus HTTP API response used by the provider
+type queryResponse struct {
+	Status string `json:"status"`
+	Error  string `json:"error"`
+	Data   struct {
+		ResultType string          `json:"resultType"`
+		Result     json.RawMessage `json:"result"`
+	} `json:"data"`
+}
+
+// getSample returns the first sample of the result, a [<timestamp>, "<value>"] pair, or nil when there is none
+// A vector holds a sample per series while a scalar is a sample itself
+func (r *queryResponse) getSample() ([]interface{}, error) {
+	switch r.Data.ResultType {
+	case "vector":
+		vector := []struct {
+			Value []interface{} `json:"value"`
+		}{}
+		if err := json.Unmarshal(r.Data.Result, &vector); err != nil || len(vector) == 0 {
+			return nil, err
+		}
+		return vector[0].Value, nil
+	case "scalar":
+		scalar := []interface{}{}
+		if err := json.Unmarshal(r.Data.Result, &scalar); err != nil {
+			return nil, err
+		}
+		return scalar, nil
+	}
+	return nil, fmt.Errorf("unsupported result type %q", r.Data.ResultType)
+}
+
+// query renders the query template for the selector and returns the value of the first sample
+func (p *prometheusProvider) query(tmpl *template.Template, selector map[string]string, window time.Duration) (*float64, error) {
+	q := &bytes.Buffer{}
+	err := tmpl.Execute(q, struct {
+		Selector string
+		Window   string
+	}{
+		Selector: promSelector(selector),
+		Window:   promDuration(window),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("could not render metrics query: %v", err)
+	}
+
+	res, err := p.client.Get(fmt.Sprintf("%s/api/v1/query?%s", p.address, url.Values{"query": {q.String()}}.Encode()))
+	if err != nil {
+		return nil, fmt.Errorf("could not query metrics: %v", err)
+	}
+	defer res.Body.Close()
+
+	body := &queryResponse{}
+	if err := json.NewDecoder(res.Body).Decode(body); err != nil {
+		return nil, fmt.Errorf("could not decode metrics response (HTTP %d): %v", res.StatusCode, err)
+	}
+	if body.Status != "success" {
+		return nil, fmt.Errorf("metrics query %q failed: %s", q.String(), body.Error)
+	}
+	sample, err := body.getSample()
+	if err != nil {
+		return nil, fmt.Errorf("metrics query %q returned a malformed result: %v", q.String(), err)
+	}
+	if len(sample) != 2 {
+		return nil, nil
+	}
+
+	raw, ok := sample[1].(string)
+	if !ok {
+		return nil, fmt.Errorf("metrics query %q returned a malformed sample", q.String())
+	}
+
+	value, err := strconv.ParseFloat(raw, 64)
+	if err != nil {
+		return nil, fmt.Errorf("metrics query %q returned a malformed sample: %v", q.String(), err)
+	}
+
+	// NaN is returned when the ratio has no requests to divide by
+	if value != value {
+		return nil, nil
+	}
+	return &value, nil
+}
+
+// promSelector renders the selector as PromQL label matchers, sorted for stable queries
+func promSelector(selector map[string]string) string {
+	keys := make([]string, 0, len(selector))
+	for k := range selector {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	matchers := make([]string, len(keys))
+	for i, k := range keys {
+		matchers[i] = fmt.Sprintf("%s=%s", k, strconv.Quote(selector[k]))
+	}
+	return strings.Join(matchers, ",")
+}
+
+// promDuration renders the duration in whole seconds as PromQL expects
+func promDuration(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64(d/time.Second))
+}
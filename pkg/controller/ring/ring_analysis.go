@@ -0,0 +1,117 @@
+package ring
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	defaultAnalysisInterval = time.Minute
+	defaultAnalysisWindow   = 5 * time.Minute
+)
+
+// analyzeRollout queries the metrics of the branch being rolled out and compares them to the thresholds
+// of the rollout analysis. The result is recorded in the rollout status
+// An analysis which can't query its metrics is inconclusive, so the rollout holds while the ring stays routed
+func (r *ReconcileRing) analyzeRollout(cr *ringsv1alpha1.Ring) ringsv1alpha1.RingAnalysisResult {
+	routing, rollout := cr.Spec.Routing, cr.Spec.Rollout
+	analysis := rollout.Analysis
+
+	// Group steps roll out the ring branch itself
+	branch := rollout.Branch
+	if branch == "" {
+		branch = routing.Branch
+	}
+	selector := getServiceSelector(&routing, branch)
+	window := getAnalysisWindow(analysis)
+
+	st := &ringsv1alpha1.RingAnalysisStatus{
+		Result: ringsv1alpha1.AnalysisPassed,
+		Time:   metav1.Now(),
+		Branch: branch,
+	}
+	failures := []string{}
+	noData := false
+
+	inconclusive := func(reason, message string) ringsv1alpha1.RingAnalysisResult {
+		setCondition(&cr.Status, ringsv1alpha1.RingAnalysisReady, corev1.ConditionFalse, reason, message)
+		st.Result = ringsv1alpha1.AnalysisInconclusive
+		st.Message = message
+		cr.Status.Rollout.Analysis = st
+		return st.Result
+	}
+	if r.Metrics == nil {
+		r.logger.Info("Rollout analysis has no metrics provider - holding the rollout")
+		return inconclusive("MetricsProviderMissing", "rollout analysis requires a metrics provider, set RING_METRICS_ADDRESS on the operator")
+	}
+
+	if analysis.MinSuccessRate != nil {
+		r.debug.Info("Querying success rate", "Branch", branch)
+		rate, err := r.Metrics.SuccessRate(selector, window)
+		if err != nil {
+			r.logger.Error(err, "Could not query success rate")
+			return inconclusive("MetricsQueryFailed", fmt.Sprintf("could not query the success rate of branch %s: %v", branch, err))
+		}
+
+		st.SuccessRate = rate
+		if rate == nil {
+			noData = true
+		} else if *rate < *analysis.MinSuccessRate {
+			failures = append(failures, fmt.Sprintf("success rate %.4f is below %.4f", *rate, *analysis.MinSuccessRate))
+		}
+	}
+
+	if analysis.MaxLatency != nil {
+		r.debug.Info("Querying latency", "Branch", branch)
+		latency, err := r.Metrics.Latency(selector, window)
+		if err != nil {
+			r.logger.Error(err, "Could not query latency")
+			return inconclusive("MetricsQueryFailed", fmt.Sprintf("could not query the latency of branch %s: %v", branch, err))
+		}
+
+		if latency == nil {
+			noData = true
+		} else {
+			st.Latency = &metav1.Duration{Duration: *latency}
+			if *latency > analysis.MaxLatency.Duration {
+				failures = append(failures, fmt.Sprintf("latency %s is above %s", *latency, analysis.MaxLatency.Duration))
+			}
+		}
+	}
+
+	switch {
+	case len(failures) > 0:
+		st.Result = ringsv1alpha1.AnalysisFailed
+		st.Message = strings.Join(failures, ", ")
+	case noData:
+		st.Result = ringsv1alpha1.AnalysisInconclusive
+		st.Message = fmt.Sprintf("no metrics for branch %s over the last %s", branch, window)
+	}
+
+	r.logger.Info("Analysed rollout", "Branch", branch, "Result", st.Result, "Message", st.Message)
+	setCondition(&cr.Status, ringsv1alpha1.RingAnalysisReady, corev1.ConditionTrue, "MetricsQueried", "")
+	cr.Status.Rollout.Analysis = st
+	return st.Result
+}
+
+// getAnalysisInterval returns the time between two analyses of the rollout
+func getAnalysisInterval(analysis *ringsv1alpha1.RingAnalysis) time.Duration {
+	if analysis.Interval == nil || analysis.Interval.Duration <= 0 {
+		return defaultAnalysisInterval
+	}
+	return analysis.Interval.Duration
+}
+
+// getAnalysisWindow returns the range of the metric queries of the analysis
+func getAnalysisWindow(analysis *ringsv1alpha1.RingAnalysis) time.Duration {
+	if analysis.Window == nil || analysis.Window.Duration <= 0 {
+		return defaultAnalysisWindow
+	}
+	return analysis.Window.Duration
+}
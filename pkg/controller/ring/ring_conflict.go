@@ -0,0 +1,210 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// FindRouteConflict returns the ring the controller would route instead of the ring on the same host, path and
+// group, or nil when there is none or the ring itself is not active
+// A ring which is not created yet claims its route after every existing ring
+func FindRouteConflict(c client.Client, cr *ringsv1alpha1.Ring) (*ringsv1alpha1.Ring, error) {
+	if !isRingActive(cr) {
+		return nil, nil
+	}
+
+	desired := getDesiredRing(cr)
+	if desired.CreationTimestamp.IsZero() {
+		desired.CreationTimestamp = metav1.Now()
+	}
+	return findRouteConflict(c, desired)
+}
+
+// findRouteConflict returns the ring the reconciler would route instead of the ring, or nil
+func (r *ReconcileRing) findRouteConflict(cr *ringsv1alpha1.Ring) (*ringsv1alpha1.Ring, error) {
+	other, err := findRouteConflict(r.Client, cr)
+	if err != nil {
+		r.logger.Error(err, "Could not list Rings")
+	}
+	return other, err
+}
+
+// findRouteConflict returns the ring already claiming the same host, path and group as the ring, or nil
+// When two rings collide the oldest one keeps the route, ties are broken on namespace and name
+func findRouteConflict(c client.Client, cr *ringsv1alpha1.Ring) (*ringsv1alpha1.Ring, error) {
+	ringList := &ringsv1alpha1.RingList{}
+	if err := c.List(context.TODO(), &client.ListOptions{}, ringList); err != nil {
+		return nil, err
+	}
+
+	for i := range ringList.Items {
+		other := &ringList.Items[i]
+		if (other.Namespace == cr.Namespace && other.Name == cr.Name) || !isRingActive(other) || other.DeletionTimestamp != nil {
+			continue
+		}
+		if !claimsBefore(other, cr) {
+			continue
+		}
+
+		desired := getDesiredRing(other)
+		if routesOverlap(&cr.Spec.Routing, &desired.Spec.Routing) {
+			return other, nil
+		}
+	}
+	return nil, nil
+}
+
+// findRouteLimit returns a ring the router can't route the ring alongside, along with the reason, or nil
+func (r *ReconcileRing) findRouteLimit(cr *ringsv1alpha1.Ring, limiter RouteLimiter) (*ringsv1alpha1.Ring, string, error) {
+	ringList := &ringsv1alpha1.RingList{}
+	if err := r.Client.List(context.TODO(), &client.ListOptions{}, ringList); err != nil {
+		r.logger.Error(err, "Could not list Rings")
+		return nil, "", err
+	}
+
+	for i := range ringList.Items {
+		other := &ringList.Items[i]
+		if (other.Namespace == cr.Namespace && other.Name == cr.Name) || !isRingActive(other) || other.DeletionTimestamp != nil {
+			continue
+		}
+		if reason := limiter.LimitRoute(cr, getDesiredRing(other), claimsBefore(other, cr)); reason != "" {
+			return other, reason, nil
+		}
+	}
+	return nil, "", nil
+}
+
+// mapRingToConflictingRings returns the other rings routed on the same host, path and group as the ring, along
+// with the rings the router can't route alongside it, so a ring which lost its route is routed again once the
+// ring keeping it is moved, disabled or deleted
+// The rings sharing the routing objects of the ring are returned as well, so a ring waiting for another ring of
+// its shared objects is routed once that ring is
+func mapRingToConflictingRings(c client.Client, router Router) handler.ToRequestsFunc {
+	limiter, _ := router.(RouteLimiter)
+	shared, _ := router.(SharedRouter)
+	return func(obj handler.MapObject) []reconcile.Request {
+		cr, ok := obj.Object.(*ringsv1alpha1.Ring)
+		if !ok {
+			return nil
+		}
+
+		rings := &ringsv1alpha1.RingList{}
+		if err := c.List(context.TODO(), &client.ListOptions{}, rings); err != nil {
+			log.Error(err, "Could not list Rings")
+			return nil
+		}
+
+		desired := getDesiredRing(cr)
+		requests := []reconcile.Request{}
+		for i := range rings.Items {
+			other := &rings.Items[i]
+			if other.Namespace == cr.Namespace && other.Name == cr.Name {
+				continue
+			}
+			otherDesired := getDesiredRing(other)
+			limited := limiter != nil && limiter.LimitRoute(otherDesired, desired, claimsBefore(cr, other)) != ""
+			sharing := shared != nil && shared.SharesRouting(cr, other)
+			if routesOverlap(&desired.Spec.Routing, &otherDesired.Spec.Routing) || limited || sharing {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+					Name:      other.Name,
+					Namespace: other.Namespace,
+				}})
+			}
+		}
+		return requests
+	}
+}
+
+// claimsBefore reports whether ring a was created before ring b
+func claimsBefore(a, b *ringsv1alpha1.Ring) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	if a.Namespace != b.Namespace {
+		return a.Namespace < b.Namespace
+	}
+	return a.Name < b.Name
+}
+
+// routesOverlap reports whether both routings send the same host, path and group to different rings
+// Rings without hosts are routed on every host and only overlap with other rings without hosts
+func routesOverlap(a, b *ringsv1alpha1.RingRouting) bool {
+	if getRingPath(a) != getRingPath(b) {
+		return false
+	}
+
+	if len(a.Hosts) > 0 || len(b.Hosts) > 0 {
+		if !hostsOverlap(normalizeHosts(a.Hosts), normalizeHosts(b.Hosts)) {
+			return false
+		}
+	}
+
+	return intersects(getRingGroupNames(a), getRingGroupNames(b))
+}
+
+// getRingGroupNames returns the names of the groups the ring is routed to
+func getRingGroupNames(routing *ringsv1alpha1.RingRouting) []string {
+	groups := getRingGroups(routing)
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = g.Name
+	}
+	return names
+}
+
+// normalizeHosts lower cases the hosts as hostnames are matched case insensitively
+func normalizeHosts(hosts []string) []string {
+	normalized := make([]string, len(hosts))
+	for i, h := range hosts {
+		normalized[i] = strings.ToLower(h)
+	}
+	return normalized
+}
+
+// hostsOverlap reports whether a request for a host can match hosts of both lists, a leading "*." matching
+// any subdomain of the rest of the host
+func hostsOverlap(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y || matchesWildcardHost(x, y) || matchesWildcardHost(y, x) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// matchesWildcardHost reports whether the wildcard host matches the host, or a subdomain of it when the host
+// is a wildcard too
+func matchesWildcardHost(wildcard, host string) bool {
+	if !strings.HasPrefix(wildcard, "*.") {
+		return false
+	}
+	suffix := wildcard[1:]
+	return len(host) > len(suffix) && strings.HasSuffix(host, suffix)
+}
+
+func intersects(a, b []string) bool {
+	for _, x := range a {
+		for _, y := range b {
+			if x == y {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// newRouteConflictError explains why the ring can't be routed
+func newRouteConflictError(cr, other *ringsv1alpha1.Ring) error {
+	return fmt.Errorf("ring %s/%s already routes path %s for the same hosts and groups", other.Namespace, other.Name, getRingPath(&cr.Spec.Routing))
+}
@@ -6,21 +6,31 @@ import (
     "github.com/go-logr/logr"
     "go.uber.org/zap/zapcore"
     "os"
+    "reflect"
     "strings"
 
     ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
 
-    traefik "github.com/containous/traefik/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+    certmanager "github.com/microsoft/ring-operator/pkg/certmanager/v1"
 
+    "github.com/operator-framework/operator-sdk/pkg/k8sutil"
+
+    appsv1 "k8s.io/api/apps/v1"
     corev1 "k8s.io/api/core/v1"
     "k8s.io/apimachinery/pkg/api/errors"
+    "k8s.io/apimachinery/pkg/api/meta"
+    metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
     "k8s.io/apimachinery/pkg/runtime"
     "k8s.io/apimachinery/pkg/types"
+    "k8s.io/client-go/discovery"
+    "k8s.io/client-go/tools/record"
     "sigs.k8s.io/controller-runtime/pkg/client"
     "sigs.k8s.io/controller-runtime/pkg/controller"
     "sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+    "sigs.k8s.io/controller-runtime/pkg/event"
     "sigs.k8s.io/controller-runtime/pkg/handler"
     "sigs.k8s.io/controller-runtime/pkg/manager"
+    "sigs.k8s.io/controller-runtime/pkg/predicate"
     "sigs.k8s.io/controller-runtime/pkg/reconcile"
     logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
     "sigs.k8s.io/controller-runtime/pkg/source"
@@ -28,19 +38,53 @@ import (
 
 var log = logf.Log.WithName("controller_ring")
 
+// ringChangedPredicate skips the updates of a Ring which only change its status, the status written by every
+// reconcile would otherwise reconcile the Ring again right away
+var ringChangedPredicate = predicate.Funcs{
+    UpdateFunc: func(e event.UpdateEvent) bool {
+        return e.MetaOld.GetGeneration() != e.MetaNew.GetGeneration() ||
+            !reflect.DeepEqual(e.MetaOld.GetLabels(), e.MetaNew.GetLabels()) ||
+            !reflect.DeepEqual(e.MetaOld.GetAnnotations(), e.MetaNew.GetAnnotations()) ||
+            !reflect.DeepEqual(e.MetaOld.GetFinalizers(), e.MetaNew.GetFinalizers()) ||
+            (e.MetaOld.GetDeletionTimestamp() == nil) != (e.MetaNew.GetDeletionTimestamp() == nil)
+    },
+}
+
 // Add creates a new Ring Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager) error {
-    return add(mgr, newReconciler(mgr))
+    router, err := newRouterFromEnv()
+    if err != nil {
+        log.Error(err, "Could not configure router")
+        return err
+    }
+
+    r, err := newReconciler(mgr, router)
+    if err != nil {
+        return err
+    }
+    return add(mgr, r, router)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager) reconcile.Reconciler {
-    return &ReconcileRing{Client: mgr.GetClient(), Scheme: mgr.GetScheme()}
+func newReconciler(mgr manager.Manager, router Router) (reconcile.Reconciler, error) {
+    metrics, err := newMetricsProviderFromEnv()
+    if err != nil {
+        log.Error(err, "Could not configure metrics provider")
+        return nil, err
+    }
+
+    return &ReconcileRing{
+        Client:   mgr.GetClient(),
+        Scheme:   mgr.GetScheme(),
+        Metrics:  metrics,
+        Recorder: mgr.GetRecorder("ring-operator"),
+        Router:   router,
+    }, nil
 }
 
 // add adds a new Controller to mgr with r as the reconcile.Reconciler
-func add(mgr manager.Manager, r reconcile.Reconciler) error {
+func add(mgr manager.Manager, r reconcile.Reconciler, router Router) error {
     debugLog := log.V(int(zapcore.DebugLevel))
     debugLog.Info("Creating a new Ring controller")
     c, err := controller.New("ring-controller", mgr, controller.Options{Reconciler: r})
@@ -48,26 +92,60 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
         return err
     }
 
-    debugLog.Info("Adding Traefik scheme to controller")
-    if err := traefik.AddToScheme(mgr.GetScheme()); err != nil {
-        log.Error(err, "Could not add traefik scheme")
+    if detector, ok := router.(APIDetector); ok {
+        debugLog.Info("Detecting the APIs served for the router", "Router", router.Name())
+        dc, err := discovery.NewDiscoveryClientForConfig(mgr.GetConfig())
+        if err != nil {
+            log.Error(err, "Could not create discovery client")
+            return err
+        }
+        if err := detector.DetectAPIs(dc); err != nil {
+            log.Error(err, "Could not detect the APIs served for the router", "Router", router.Name())
+            return err
+        }
+    }
+
+    debugLog.Info("Adding router scheme to controller", "Router", router.Name())
+    if err := router.AddToScheme(mgr.GetScheme()); err != nil {
+        log.Error(err, "Could not add router scheme", "Router", router.Name())
         return err
     }
 
+    if migrator, ok := router.(LegacyMigrator); ok {
+        // The cache of the manager is not started yet, the migration reads from the API server
+        debugLog.Info("Migrating the routing objects of legacy APIs", "Router", router.Name())
+        cl, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme()})
+        if err != nil {
+            log.Error(err, "Could not create client")
+            return err
+        }
+        // The Role of the operator only grants access to the routing objects of the watched namespace
+        namespace, err := k8sutil.GetWatchNamespace()
+        if err != nil {
+            log.Error(err, "Could not get watch namespace")
+            return err
+        }
+        // The operator doesn't start until the migration succeeds, the rings are still routed through the legacy
+        // objects left behind meanwhile
+        if err := migrator.MigrateLegacy(cl, namespace); err != nil {
+            log.Error(err, "Could not migrate the routing objects of legacy APIs", "Router", router.Name())
+            return err
+        }
+    }
+
     debugLog.Info("Adding watch for Ring resource")
-    err = c.Watch(&source.Kind{Type: &ringsv1alpha1.Ring{}}, &handler.EnqueueRequestForObject{})
+    err = c.Watch(&source.Kind{Type: &ringsv1alpha1.Ring{}}, &handler.EnqueueRequestForObject{}, ringChangedPredicate)
     if err != nil {
         log.Error(err, "Could not watch resource Ring")
         return err
     }
 
-    debugLog.Info("Adding watch for child Traefik Middleware")
-    err = c.Watch(&source.Kind{Type: &traefik.Middleware{}}, &handler.EnqueueRequestForOwner{
-        IsController: true,
-        OwnerType:    &ringsv1alpha1.Ring{},
-    })
+    debugLog.Info("Adding watch for Rings conflicting with the route of a ring or limited by it")
+    err = c.Watch(&source.Kind{Type: &ringsv1alpha1.Ring{}}, &handler.EnqueueRequestsFromMapFunc{
+        ToRequests: mapRingToConflictingRings(mgr.GetClient(), router),
+    }, ringChangedPredicate)
     if err != nil {
-        log.Error(err, "Could not watch child resource Middleware")
+        log.Error(err, "Could not watch resource Ring")
         return err
     }
 
@@ -81,16 +159,55 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
         return err
     }
 
-    debugLog.Info("Adding watch for child Traefik IngressRoute")
-    err = c.Watch(&source.Kind{Type: &traefik.IngressRoute{}}, &handler.EnqueueRequestForOwner{
-        IsController: true,
-        OwnerType:    &ringsv1alpha1.Ring{},
+    debugLog.Info("Adding watch for Deployments of the ring branches")
+    err = c.Watch(&source.Kind{Type: &appsv1.Deployment{}}, &handler.EnqueueRequestsFromMapFunc{
+        ToRequests: mapDeploymentToRings(mgr.GetClient()),
+    })
+    if err != nil {
+        log.Error(err, "Could not watch resource Deployment")
+        return err
+    }
+
+    debugLog.Info("Adding watch for Endpoints of the child Services")
+    err = c.Watch(&source.Kind{Type: &corev1.Endpoints{}}, &handler.EnqueueRequestsFromMapFunc{
+        ToRequests: mapEndpointsToRing(mgr.GetClient()),
     })
     if err != nil {
-        log.Error(err, "Could not watch child resource IngressRoute")
+        log.Error(err, "Could not watch resource Endpoints")
         return err
     }
 
+    for _, t := range router.OwnedTypes() {
+        debugLog.Info("Adding watch for child routing object", "Kind", fmt.Sprintf("%T", t.Object))
+        // Every ring owning a shared routing object is reconciled when it changes
+        err = c.Watch(&source.Kind{Type: t.Object}, &handler.EnqueueRequestForOwner{
+            IsController: !t.Shared,
+            OwnerType:    &ringsv1alpha1.Ring{},
+        })
+        if err != nil {
+            log.Error(err, "Could not watch child routing object", "Kind", fmt.Sprintf("%T", t.Object))
+            return err
+        }
+    }
+
+    if isCertManagerEnabled() {
+        debugLog.Info("Adding cert-manager scheme to controller")
+        if err := certmanager.AddToScheme(mgr.GetScheme()); err != nil {
+            log.Error(err, "Could not add cert-manager scheme")
+            return err
+        }
+
+        debugLog.Info("Adding watch for child cert-manager Certificate")
+        err = c.Watch(&source.Kind{Type: &certmanager.Certificate{}}, &handler.EnqueueRequestForOwner{
+            IsController: true,
+            OwnerType:    &ringsv1alpha1.Ring{},
+        })
+        if err != nil {
+            log.Error(err, "Could not watch child resource Certificate")
+            return err
+        }
+    }
+
     return nil
 }
 
@@ -103,17 +220,27 @@ type ReconcileRing struct {
     // that reads objects from the cache and writes to the apiserver
     Client client.Client
     Scheme *runtime.Scheme
-    logger logr.Logger
-    debug  logr.InfoLogger
+    // Metrics is queried by the rollout analysis, it is nil when no provider is configured
+    Metrics MetricsProvider
+    // Recorder records the Events of the rings, no Event is recorded when it is nil
+    Recorder record.EventRecorder
+    // Router produces the routing objects of the rings, the router selected by RING_ROUTER is used when it is nil
+    Router Router
+    logger   logr.Logger
+    debug    logr.InfoLogger
 }
 
 // Reconcile reads that state of the cluster for a Ring object and makes changes based on the state read
 // and what is in the Ring.Spec
 // Steps:
-// 1. Create Middleware specific to this Ring
-//		a. StripPrefix
-// 2. Create Service to link Deployment
-// 3. Create IngressRoute to link Service
+// 1. Create Service to link Deployment
+//		a. Services for the branches of a split
+// 2. Create the routing objects of the router (eg: Traefik Middlewares and weighted TraefikService)
+// 3. Create the route of the router (eg: Traefik IngressRoute) to link Service
+//		a. Only once the Services have ready endpoints when the ring requires them
+// A ring on standby only keeps its Service, a disabled ring has every child removed
+// The routing of the children follows the current step of the ring rollout, a ring with a rollout
+// in progress is requeued once the current step has baked
 // Note:
 // The Controller will requeue the Request to be processed again if the returned error is non-nil or
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
@@ -139,59 +266,266 @@ func (r *ReconcileRing) Reconcile(request reconcile.Request) (reconcile.Result,
     }
 
     r.debug.Info("Found the Ring instance")
-    if !instance.Spec.Deploy {
-        r.debug.Info("Ring deploy is set to false - don't requeue the request")
-        return reconcile.Result{Requeue: false}, nil
+    result, err := r.reconcileRing(instance)
+
+    r.debug.Info("Recording reconciliation result in Ring status")
+    if statusErr := r.updateStatus(instance, err); statusErr != nil && err == nil {
+        return reconcile.Result{}, statusErr
+    }
+
+    return result, err
+}
+
+// reconcileRing runs the reconciliation steps for a Ring instance
+// Each step records its own condition on the instance status, which is written back by the caller
+func (r *ReconcileRing) reconcileRing(instance *ringsv1alpha1.Ring) (reconcile.Result, error) {
+    status := &instance.Status
+
+    router, err := r.router()
+    if err != nil {
+        r.logger.Error(err, "Could not select the router of the ring")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "RouterFailed", err.Error())
+        return reconcile.Result{}, err
     }
 
     r.debug.Info("Setting finalizer to run when deletion happens")
-    if err := r.handleDeletion(instance); err != nil {
+    if err := r.handleDeletion(instance, router); err != nil {
         r.logger.Error(err, "Error handling deletion finalizer")
         return reconcile.Result{}, err
     }
 
+    state := getRingState(instance)
+    status.State = state
+    if state == ringsv1alpha1.RingDisabled {
+        r.logger.Info("Ring is disabled - removing its children")
+        if err := r.disableRing(instance, router, status); err != nil {
+            r.logger.Error(err, "Could not remove the children of the ring")
+            setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "WithdrawFailed", err.Error())
+            return reconcile.Result{}, err
+        }
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "Disabled", "Ring is disabled, its children are removed")
+        setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "Disabled", "Ring is disabled")
+        return reconcile.Result{}, nil
+    }
+
+    // The route of a ring on standby is withdrawn even when its spec is invalid
+    if state == ringsv1alpha1.RingStandby {
+        r.logger.Info("Ring is on standby - withdrawing its route")
+        if err := r.withdrawRoute(instance, router, status); err != nil {
+            r.logger.Error(err, "Could not withdraw the route of the ring")
+            setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "WithdrawFailed", err.Error())
+            return reconcile.Result{}, err
+        }
+    }
+
+    r.debug.Info("Validating Ring")
+    defaulted := instance.DeepCopy()
+    DefaultRing(defaulted)
+    if errs := ValidateRing(defaulted, router); len(errs) > 0 {
+        // The ring is reconciled again once its spec is fixed, retrying it can't succeed before
+        err := errs.ToAggregate()
+        r.logger.Error(err, "Ring is invalid")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "Invalid", err.Error())
+        setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "Invalid", "Ring spec is invalid")
+        r.recordEvent(instance, corev1.EventTypeWarning, "Invalid", err.Error())
+        return reconcile.Result{}, nil
+    }
+
+    if state == ringsv1alpha1.RingStandby {
+        r.logger.Info("Ring is on standby - keeping its Service")
+        svc, err := r.createOrUpdateService(defaulted, defaulted.Name, defaulted.Spec.Routing.Branch)
+        if err != nil {
+            r.logger.Error(err, "Could not create or update service")
+            setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "ServiceFailed", err.Error())
+            return reconcile.Result{}, err
+        }
+        status.ServiceName = svc.Name
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "Standby", "Ring is on standby, its Service is kept without a route")
+        setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "Standby", "Ring is on standby")
+        return reconcile.Result{}, nil
+    }
+
+    r.debug.Info("Progressing rollout")
+    requeueAfter, err := r.progressRollout(instance)
+    if err != nil {
+        r.logger.Error(err, "Could not progress rollout")
+        return reconcile.Result{}, err
+    }
+
+    // The children are built from the routing of the current rollout step
+    desired := getDesiredRing(instance)
+
     useADGroups := os.Getenv("AZURE_AD_ENABLED")
     if strings.ToLower(useADGroups) == "true" {
-        r.debug.Info("Checking if AD group already exists")
-        if adGroupExists, err := r.adGroupExists(instance); err != nil {
-            r.logger.Error(err, "Could not check if AD Group Exists")
+        for _, group := range getRingGroups(&desired.Spec.Routing) {
+            r.debug.Info("Checking if AD group already exists", "Group", group.Name)
+            if adGroupExists, err := r.adGroupExists(group.Name); err != nil {
+                r.logger.Error(err, "Could not check if AD Group Exists")
+                setCondition(status, ringsv1alpha1.RingIdentityReady, corev1.ConditionFalse, "ADGroupLookupFailed", err.Error())
+                return reconcile.Result{}, err
+            } else if !adGroupExists {
+                r.debug.Info("AD Group does not exist", "Group", group.Name)
+                if err := r.createADGroup(group.Name); err != nil {
+                    r.logger.Error(err, "Could not create AD group")
+                    setCondition(status, ringsv1alpha1.RingIdentityReady, corev1.ConditionFalse, "ADGroupCreateFailed", err.Error())
+                    return reconcile.Result{}, err
+                }
+            }
+        }
+        setCondition(status, ringsv1alpha1.RingIdentityReady, corev1.ConditionTrue, "ADGroupExists", "")
+    } else {
+        setCondition(status, ringsv1alpha1.RingIdentityReady, corev1.ConditionTrue, "ADDisabled", "AAD groups are not enabled for the operator")
+    }
+
+    r.debug.Info("Checking for rings routed on the same host, path and group")
+    other, err := r.findRouteConflict(desired)
+    if err != nil {
+        return reconcile.Result{}, err
+    }
+    if other != nil {
+        // The older ring keeps the route, the ring is reconciled again when the other ring changes
+        conflict := newRouteConflictError(desired, other)
+        r.logger.Info("Ring route conflicts with another ring - withdrawing its route", "Reason", conflict.Error())
+        if err := r.withdrawRoute(instance, router, status); err != nil {
+            r.logger.Error(err, "Could not withdraw the route of the ring")
+            setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "WithdrawFailed", err.Error())
+            return reconcile.Result{}, err
+        }
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "RouteConflict", conflict.Error())
+        setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "RouteConflict", "Requests of the ring are routed to another ring")
+        r.recordEvent(instance, corev1.EventTypeWarning, "RouteConflict", conflict.Error())
+        return reconcile.Result{RequeueAfter: requeueAfter}, nil
+    }
+    if limiter, ok := router.(RouteLimiter); ok {
+        r.debug.Info("Checking for rings the router can't route alongside the ring")
+        other, reason, err := r.findRouteLimit(desired, limiter)
+        if err != nil {
             return reconcile.Result{}, err
-        } else if !adGroupExists {
-            r.debug.Info("AD Group does not exist")
-            if err := r.createADGroup(instance); err != nil {
-                r.logger.Error(err, "Could not create AD group")
+        }
+        if other != nil {
+            // Like for a conflict, the ring is reconciled again when the other ring changes
+            limit := fmt.Errorf("ring %s/%s can't be routed alongside ring %s/%s: %s", desired.Namespace, desired.Name, other.Namespace, other.Name, reason)
+            r.logger.Info("Ring route exceeds the limits of the router - withdrawing its route", "Reason", limit.Error())
+            if err := r.withdrawRoute(instance, router, status); err != nil {
+                r.logger.Error(err, "Could not withdraw the route of the ring")
+                setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "WithdrawFailed", err.Error())
                 return reconcile.Result{}, err
             }
+            setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "RouterLimit", limit.Error())
+            setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "RouterLimit", "The router can't route the ring alongside another ring")
+            r.recordEvent(instance, corev1.EventTypeWarning, "RouterLimit", limit.Error())
+            return reconcile.Result{RequeueAfter: requeueAfter}, nil
         }
     }
 
-    r.debug.Info("Ensure StripPrefix exists")
-    if _, err := r.createOrUpdateStripPrefix(instance); err != nil {
-        r.logger.Error(err, "Could not create or update stripPrefix")
+    r.debug.Info("Verifying the workloads of the ring")
+    reason, problems, err := r.verifyWorkloads(desired)
+    if err != nil {
+        return reconcile.Result{}, err
+    }
+    r.reportWorkloads(instance, status, reason, problems)
+
+    r.debug.Info("Rendering the routing of the ring", "Router", router.Name())
+    routing, err := router.Route(desired)
+    if err != nil {
+        r.logger.Error(err, "Could not render the routing of the ring")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "RouteFailed", err.Error())
         return reconcile.Result{}, err
     }
 
     r.debug.Info("Ensure Service exists")
-    if _, err := r.createOrUpdateService(instance); err != nil {
+    svc, err := r.createOrUpdateService(desired, desired.Name, desired.Spec.Routing.Branch)
+    if err != nil {
         r.logger.Error(err, "Could not create or update service")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "ServiceFailed", err.Error())
         return reconcile.Result{}, err
     }
+    status.ServiceName = svc.Name
 
-    r.debug.Info("Ensure IngressRoute exists")
-    if _, err := r.createOrUpdateIngressRoute(instance); err != nil {
-        r.logger.Error(err, "Could not create or update ingress route")
+    r.debug.Info("Ensure weighted split exists")
+    if err := r.reconcileSplit(desired); err != nil {
+        r.logger.Error(err, "Could not reconcile weighted split")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "SplitFailed", err.Error())
         return reconcile.Result{}, err
     }
 
+    r.debug.Info("Ensure Certificate exists")
+    certName, err := r.reconcileCertificate(desired, status)
+    if err != nil {
+        r.logger.Error(err, "Could not reconcile certificate")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "CertificateFailed", err.Error())
+        return reconcile.Result{}, err
+    }
+    status.CertificateName = certName
+
+    r.debug.Info("Checking the endpoints of the ring")
+    routed, err := r.checkEndpoints(desired, status)
+    if err != nil {
+        return reconcile.Result{}, err
+    }
+    if !routed {
+        r.logger.Info("Ring has no ready endpoints - falling back to the production ring")
+    }
+
+    r.debug.Info("Ensure routing objects exist")
+    if err := r.reconcileRouting(desired, router, routing, routed); err != nil {
+        r.logger.Error(err, "Could not reconcile the routing objects")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "RoutingFailed", err.Error())
+        return reconcile.Result{}, err
+    }
+    sharedRouteName, err := r.reconcileSharedRouting(desired, router, routed)
+    if waiting, ok := err.(*routeWaitingError); ok {
+        r.logger.Info("Ring is waiting for another ring to be routed", "Reason", waiting.Error())
+        status.IngressRouteName, status.Match = "", ""
+        removeCondition(status, ringsv1alpha1.RingRouteAccepted)
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, waiting.reason, waiting.message)
+        setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, waiting.reason, "Ring is routed once the ring it waits for is routed")
+        return reconcile.Result{RequeueAfter: requeueAfter}, nil
+    }
+    if err != nil {
+        r.logger.Error(err, "Could not reconcile the shared routing objects")
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "RoutingFailed", err.Error())
+        return reconcile.Result{}, err
+    }
+    status.MiddlewareNames = routing.MiddlewareNames
+    status.IngressRouteName, status.Match = "", ""
+    if !routed {
+        removeCondition(status, ringsv1alpha1.RingRouteAccepted)
+        setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionFalse, "WaitingForEndpoints", "Ring is routed once its branches have ready endpoints")
+        setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "WaitingForEndpoints", "Requests of the ring fall back to the production ring")
+        return reconcile.Result{RequeueAfter: requeueAfter}, nil
+    }
+
+    if route, err := meta.Accessor(routing.Route); err == nil {
+        status.IngressRouteName = route.GetName()
+    } else if sharedRouteName != "" {
+        status.IngressRouteName = sharedRouteName
+    }
+    status.Match = routing.Match
+
+    if reporter, ok := router.(RouteStatusReporter); ok {
+        accepted, reason, message := reporter.RouteAccepted(routing.Route)
+        setCondition(status, ringsv1alpha1.RingRouteAccepted, accepted, reason, message)
+        if accepted == corev1.ConditionFalse {
+            r.logger.Info("Route of the ring was not accepted", "Reason", reason, "Message", message)
+            setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionTrue, "Configured", "")
+            setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "RouteNotAccepted", message)
+            return reconcile.Result{RequeueAfter: requeueAfter}, nil
+        }
+    }
+    setCondition(status, ringsv1alpha1.RingRoutingConfigured, corev1.ConditionTrue, "Configured", "")
+    setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionTrue, "Reconciled", "")
+
     r.logger.Info("Reconciliation finished")
-    return reconcile.Result{}, err
+    return reconcile.Result{RequeueAfter: requeueAfter}, nil
 }
 
 // handleDeletion sets up this ring for deletion
 // It checks if the ring is marked for deletion
-// If it's marked for deletion then it should clean up all off-cluster resources (eg: AAD Groups)
+// If it's marked for deletion then it should clean up all off-cluster resources (eg: AAD Groups) and the routing
+// it shares with other rings, which is not garbage collected while they own it
 // If it isn't marked for deletion then it should ensure that the finalizer is set on the instance
-func (r *ReconcileRing) handleDeletion(cr *ringsv1alpha1.Ring) error {
+func (r *ReconcileRing) handleDeletion(cr *ringsv1alpha1.Ring, router Router) error {
     r.debug.Info("Starting handling deletion")
     r.debug.Info("Check if Ring is marked for deletion")
     if isRingMarkedForDeletion := cr.GetDeletionTimestamp(); isRingMarkedForDeletion != nil {
@@ -209,6 +543,12 @@ func (r *ReconcileRing) handleDeletion(cr *ringsv1alpha1.Ring) error {
                 }
             }
 
+            r.debug.Info("Removing the ring from its shared routing")
+            if _, err := r.reconcileSharedRouting(cr, router, false); err != nil {
+                r.logger.Error(err, "Could not remove the ring from its shared routing")
+                return err
+            }
+
             r.debug.Info("Removing finalizer from Ring resource to allow deletion")
             cr.SetFinalizers(remove(cr.GetFinalizers(), ringFinalizer))
 
@@ -241,16 +581,16 @@ func (r *ReconcileRing) handleDeletion(cr *ringsv1alpha1.Ring) error {
     return nil
 }
 
-// createOrUpdateService ensures the Service exists with the up to date information in the Ring instance
+// createOrUpdateService ensures the named Service for the branch exists with the up to date information in the Ring instance
 // It returns created or updated Service and any error
-func (r *ReconcileRing) createOrUpdateService(cr *ringsv1alpha1.Ring) (*corev1.Service, error) {
+func (r *ReconcileRing) createOrUpdateService(cr *ringsv1alpha1.Ring, name, branch string) (*corev1.Service, error) {
     r.logger.Info("createOrUpdateService")
 
     svcFound := &corev1.Service{}
     r.logger.Info("Finding Service")
-    err := r.Client.Get(context.TODO(), types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, svcFound)
+    err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, svcFound)
     if err != nil && errors.IsNotFound(err) {
-        svc := r.newServiceForCR(cr)
+        svc := r.newServiceForCR(cr, name, branch)
 
         r.debug.Info("Setting Ring as owner of service")
         if err := controllerutil.SetControllerReference(cr, svc, r.Scheme); err != nil {
@@ -269,8 +609,16 @@ func (r *ReconcileRing) createOrUpdateService(cr *ringsv1alpha1.Ring) (*corev1.S
         r.logger.Error(err, "Could not get existing Service")
         return nil, err
     } else {
+        // The Service of another ring may have the same name, such as ring hello-world and the branch world of
+        // ring hello
+        if owner := metav1.GetControllerOf(svcFound); owner != nil && !metav1.IsControlledBy(svcFound, cr) {
+            err := errors.NewConflict(corev1.Resource("services"), name, fmt.Errorf("the Service is controlled by %s %s", owner.Kind, owner.Name))
+            r.logger.Error(err, "Service is controlled by another object", "Owner.Kind", owner.Kind, "Owner.Name", owner.Name)
+            return nil, err
+        }
+
         r.logger.Info("Updating service")
-        svc := r.updateServiceForCR(svcFound, cr)
+        svc := r.updateServiceForCR(svcFound, cr, branch)
         if err = r.Client.Update(context.TODO(), svc); err != nil {
             r.logger.Info("Could not update service")
             return nil, err
@@ -278,82 +626,3 @@ func (r *ReconcileRing) createOrUpdateService(cr *ringsv1alpha1.Ring) (*corev1.S
         return svc, nil
     }
 }
-
-// createOrUpdateIngressRoute ensures the IngressRoute exists with the up to date information in the Ring instance
-// It returns created or updated IngressRoute and any error
-func (r *ReconcileRing) createOrUpdateIngressRoute(cr *ringsv1alpha1.Ring) (*traefik.IngressRoute, error) {
-    r.logger.Info("createOrUpdateIngressRoute")
-
-    ingFound := &traefik.IngressRoute{}
-    r.logger.Info("Finding IngressRoute")
-    err := r.Client.Get(context.TODO(), types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, ingFound)
-    if err != nil && errors.IsNotFound(err) {
-        ing := r.newIngressRouteForCR(cr)
-
-        r.debug.Info("Setting Ring as owner of IngressRoute")
-        if err := controllerutil.SetControllerReference(cr, ing, r.Scheme); err != nil {
-            r.logger.Error(err, "Could not set Ring as owner of IngressRoute")
-            return nil, err
-        }
-
-        r.logger.Info("Creating a new IngressRoute")
-        if err = r.Client.Create(context.TODO(), ing); err != nil {
-            r.logger.Error(err, "Could not create IngressRoute")
-            return nil, err
-        }
-        return ing, nil
-    } else if err != nil {
-        r.logger.Error(err, "Could not get existing IngressRoute")
-        return nil, err
-    } else {
-        r.logger.Info("Updating IngressRoute")
-
-        ing := r.updateIngressRouteForCR(ingFound, cr)
-        if err = r.Client.Update(context.TODO(), ing); err != nil {
-            r.logger.Info("Could not update IngressRoute")
-            return nil, err
-        }
-        return ing, nil
-    }
-}
-
-// createOrUpdateStripPrefix ensures the StripPrefix Middleware exists with the up to date information in the Ring instance
-// It returns created or updated StripPrefix Middleware and any error
-func (r *ReconcileRing) createOrUpdateStripPrefix(cr *ringsv1alpha1.Ring) (*traefik.Middleware, error) {
-    r.debug.Info("createOrUpdateStripPrefix")
-
-    r.logger.Info("Finding StripPrefix")
-    mFound := &traefik.Middleware{}
-    mName := fmt.Sprintf("%s-stripprefix", cr.Name)
-
-    err := r.Client.Get(context.TODO(), types.NamespacedName{Name: mName, Namespace: cr.Namespace}, mFound)
-    if err != nil && errors.IsNotFound(err) {
-        m := r.newStripPrefixForCR(cr)
-
-        r.debug.Info("Setting Ring as owner of StripPrefix")
-        if err := controllerutil.SetControllerReference(cr, m, r.Scheme); err != nil {
-            r.logger.Error(err, "Could not set Ring as owner of Middleware")
-            return nil, err
-        }
-
-        r.logger.Info("Creating a new StripPrefix")
-        if err = r.Client.Create(context.TODO(), m); err != nil {
-            r.logger.Error(err, "Could not create StripPrefix")
-            return nil, err
-        }
-
-        return m, nil
-    } else if err != nil {
-        r.logger.Error(err, "Could not get existing StripPrefix")
-        return nil, err
-    } else {
-        r.logger.Info("Updating StripPrefix")
-
-        m := r.updateStripPrefixForCR(mFound, cr)
-        if err = r.Client.Update(context.TODO(), m); err != nil {
-            r.logger.Info("Could not update StripPrefix")
-            return nil, err
-        }
-        return m, nil
-    }
-}
@@ -4,13 +4,27 @@ import (
 	"context"
 	"fmt"
 	"github.com/microsoft/ring-operator/pkg/controller/ring"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
 	"testing"
+	"time"
 
-	traefik "github.com/containous/traefik/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	certmanager "github.com/microsoft/ring-operator/pkg/certmanager/v1"
+	gatewayv1 "github.com/microsoft/ring-operator/pkg/gatewayapi/v1"
+	istio "github.com/microsoft/ring-operator/pkg/istio/v1beta1"
+	networkingv1 "github.com/microsoft/ring-operator/pkg/networking/v1"
+	specs "github.com/microsoft/ring-operator/pkg/smi/specs/v1alpha4"
+	split "github.com/microsoft/ring-operator/pkg/smi/split/v1alpha4"
+	traefik "github.com/microsoft/ring-operator/pkg/traefik/v1alpha1"
 
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
 
@@ -20,6 +34,11 @@ import (
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	clienttesting "k8s.io/client-go/testing"
+	"k8s.io/client-go/tools/record"
 	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
 )
 
@@ -103,9 +122,10 @@ func TestReconcile(t *testing.T) {
 
 	// Add Known CustomResourceDefinitions to the cluster scheme
 	s := scheme.Scheme
-	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{})
-	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{})
-	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{})
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
 	cl := fake.NewFakeClient(objs...)
 
 	// Create a request for reconciliation
@@ -169,9 +189,10 @@ func TestReconcileProduction(t *testing.T) {
 
 	// Add Known CustomResourceDefinitions to the cluster scheme
 	s := scheme.Scheme
-	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{})
-	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{})
-	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{})
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
 	cl := fake.NewFakeClient(objs...)
 
 	// Create a request for reconciliation
@@ -219,21 +240,22 @@ func TestReconcileProduction(t *testing.T) {
 func TestReconcileDeployFalse(t *testing.T) {
 	logf.SetLogger(logf.ZapLogger(true))
 
-	// Create copy of instance with deploy: false
 	namespace := "default"
 	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
 	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
 	group := "canary"
 
 	objs := []runtime.Object{
-		createRing(name, namespace, group, false, selector),
+		createRing(name, namespace, group, true, selector),
 		createDeployment(name, namespace, selector),
 	}
 
 	// Add Known CustomResourceDefinitions to the cluster scheme
 	s := scheme.Scheme
-	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{})
-	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{})
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
 	cl := fake.NewFakeClient(objs...)
 
 	// Create a request for reconciliation
@@ -245,9 +267,2525 @@ func TestReconcileDeployFalse(t *testing.T) {
 		},
 	}
 
-	// Reconcile request
+	// Reconcile the deployed ring
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.NoError(t, err)
+	stripName := types.NamespacedName{Name: name + "-stripprefix", Namespace: namespace}
+	err = cl.Get(context.TODO(), stripName, &traefik.Middleware{})
+	require.NoError(t, err)
+
+	// Setting deploy to false withdraws the route and keeps the Service
+	instance := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Deploy = false
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+
 	res, err := r.Reconcile(req)
 	require.NoError(t, err)
-	require.NotNil(t, res)
 	require.False(t, res.Requeue)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), stripName, &traefik.Middleware{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), req.NamespacedName, &corev1.Service{})
+	require.NoError(t, err)
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, instance)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RingStandby, instance.Status.State)
+	require.Empty(t, instance.Status.IngressRouteName)
+	require.Equal(t, name, instance.Status.ServiceName)
+
+	// A ring set on standby while its spec is invalid has its route withdrawn too
+	instance.Spec.Deploy = true
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.NoError(t, err)
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Deploy = false
+	instance.Spec.Routing.Ports = nil
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.True(t, errors.IsNotFound(err))
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, instance)
+	require.NoError(t, err)
+	require.Empty(t, instance.Status.IngressRouteName)
+	for _, c := range instance.Status.Conditions {
+		if c.Type == ringsv1alpha1.RingRoutingConfigured {
+			require.Equal(t, "Invalid", c.Reason)
+		}
+	}
+
+	// A disabled ring has every child removed
+	instance.Spec.State = ringsv1alpha1.RingDisabled
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, &corev1.Service{})
+	require.True(t, errors.IsNotFound(err))
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, instance)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RingDisabled, instance.Status.State)
+	require.Empty(t, instance.Status.ServiceName)
+}
+
+// TestReconcileStatus tests that the outcome of the reconcile is recorded on the Ring status
+func TestReconcileStatus(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	group := "canary"
+	expectedRoute := fmt.Sprintf("PathPrefix(`/%s/%s`) && Headers(`group`, `%s`)", selector["service"], selector["version"], group)
+
+	instance := createRing(name, namespace, group, true, selector)
+	instance.Generation = 3
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure the status reflects the created children
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), found.Status.ObservedGeneration)
+	require.Equal(t, name, found.Status.ServiceName)
+	require.Equal(t, name, found.Status.IngressRouteName)
+	require.Equal(t, []string{fmt.Sprintf("%s-stripprefix", name)}, found.Status.MiddlewareNames)
+	require.Equal(t, expectedRoute, found.Status.Match)
+	require.Empty(t, found.Status.LastError)
+
+	conditions := map[ringsv1alpha1.RingConditionType]corev1.ConditionStatus{}
+	for _, c := range found.Status.Conditions {
+		conditions[c.Type] = c.Status
+	}
+	require.Equal(t, corev1.ConditionTrue, conditions[ringsv1alpha1.RingReady])
+	require.Equal(t, corev1.ConditionTrue, conditions[ringsv1alpha1.RingRoutingConfigured])
+	require.Equal(t, corev1.ConditionTrue, conditions[ringsv1alpha1.RingIdentityReady])
+	require.Equal(t, corev1.ConditionFalse, conditions[ringsv1alpha1.RingDegraded])
+}
+
+// TestReconcileSplit tests a ring splitting its traffic by weight across branches
+func TestReconcileSplit(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "master"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	group := "*"
+
+	instance := createRing(name, namespace, group, true, selector)
+	instance.Spec.Routing.Split = []ringsv1alpha1.RingBranchWeight{
+		{Branch: "master", Weight: 95},
+		{Branch: "canary", Weight: 5},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure a Service was created for the canary branch
+	svc := &corev1.Service{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-canary", name)}, svc)
+	require.NoError(t, err)
+	require.Equal(t, "canary", svc.Spec.Selector["branch"])
+
+	// Ensure the TraefikService splits the traffic by weight
+	ts := &traefik.TraefikService{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-weighted", name)}, ts)
+	require.NoError(t, err)
+	require.NotNil(t, ts.Spec.Weighted)
+	require.Len(t, ts.Spec.Weighted.Services, 2)
+	require.Equal(t, name, ts.Spec.Weighted.Services[0].Name)
+	require.Equal(t, 95, *ts.Spec.Weighted.Services[0].Weight)
+	require.Equal(t, fmt.Sprintf("%s-canary", name), ts.Spec.Weighted.Services[1].Name)
+	require.Equal(t, 5, *ts.Spec.Weighted.Services[1].Weight)
+
+	// Ensure the IngressRoute sends the traffic to the TraefikService
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, []traefik.Service{{Name: ts.Name, Kind: "TraefikService"}}, ing.Spec.Routes[0].Services)
+
+	// Removing the split removes the canary Service and the TraefikService
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.Split = nil
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-canary", name)}, svc)
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: ts.Name}, ts)
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, name, ing.Spec.Routes[0].Services[0].Name)
+
+	// The Service of another ring named like the Service of a branch is not taken over
+	controller := true
+	other := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-canary", name),
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "rings.microsoft.com/v1alpha1", Kind: "Ring", Name: fmt.Sprintf("%s-canary", name), UID: "other", Controller: &controller},
+			},
+		},
+		Spec: corev1.ServiceSpec{Selector: map[string]string{"branch": "other"}},
+	}
+	err = cl.Create(context.TODO(), other)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.Split = instance.Spec.Routing.Split
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.Error(t, err)
+	require.True(t, errors.IsConflict(err))
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: other.Name}, svc)
+	require.NoError(t, err)
+	require.Equal(t, "other", svc.Spec.Selector["branch"])
+}
+
+// TestReconcileRollout tests a ring moving through the steps of its rollout
+func TestReconcileRollout(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "master"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	group := "*"
+	firstWeight, secondWeight := 10, 50
+
+	instance := createRing(name, namespace, group, true, selector)
+	instance.Spec.Rollout = &ringsv1alpha1.RingRollout{
+		Branch: "canary",
+		Steps: []ringsv1alpha1.RingRolloutStep{
+			{Weight: &firstWeight, Bake: metav1.Duration{Duration: time.Hour}},
+			{Weight: &secondWeight, Bake: metav1.Duration{Duration: time.Hour}},
+		},
+	}
+	started := metav1.NewTime(time.Now().Add(-90 * time.Minute))
+	instance.Status.Rollout = &ringsv1alpha1.RingRolloutStatus{
+		Phase:         ringsv1alpha1.RolloutProgressing,
+		Step:          0,
+		StartTime:     &started,
+		StepStartTime: &started,
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// The first step has baked, the rollout moves to the second one
+	res, err := r.Reconcile(req)
+	require.NoError(t, err)
+	require.True(t, res.RequeueAfter > 59*time.Minute && res.RequeueAfter <= time.Hour)
+
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RolloutProgressing, found.Status.Rollout.Phase)
+	require.Equal(t, 1, found.Status.Rollout.Step)
+
+	ts := &traefik.TraefikService{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-weighted", name)}, ts)
+	require.NoError(t, err)
+	require.Equal(t, 50, *ts.Spec.Weighted.Services[0].Weight)
+	require.Equal(t, 50, *ts.Spec.Weighted.Services[1].Weight)
+
+	// Pausing holds the rollout without requeueing
+	found.Annotations = map[string]string{"rings.microsoft.com/rollout-paused": "true"}
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	res, err = r.Reconcile(req)
+	require.NoError(t, err)
+	require.Equal(t, time.Duration(0), res.RequeueAfter)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RolloutPaused, found.Status.Rollout.Phase)
+	require.Equal(t, 1, found.Status.Rollout.Step)
+
+	// Aborting routes the ring as if it had no rollout
+	found.Annotations = map[string]string{"rings.microsoft.com/rollout-abort": "true"}
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RolloutAborted, found.Status.Rollout.Phase)
+	require.NotNil(t, found.Status.Rollout.CompletionTime)
+
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, name, ing.Spec.Routes[0].Services[0].Name)
+	require.Empty(t, ing.Spec.Routes[0].Services[0].Kind)
+
+	// Editing the steps of a completed rollout starts the new rollout over from its first step
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Annotations = nil
+	found.Status.Rollout.Phase = ringsv1alpha1.RolloutCompleted
+	found.Status.Rollout.Step = 1
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RolloutCompleted, found.Status.Rollout.Phase)
+	require.NotEmpty(t, found.Status.Rollout.SpecHash)
+
+	newWeight := 20
+	found.Spec.Rollout.Steps[0].Weight = &newWeight
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RolloutProgressing, found.Status.Rollout.Phase)
+	require.Equal(t, 0, found.Status.Rollout.Step)
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-weighted", name)}, ts)
+	require.NoError(t, err)
+	require.Equal(t, 80, *ts.Spec.Weighted.Services[0].Weight)
+	require.Equal(t, 20, *ts.Spec.Weighted.Services[1].Weight)
+}
+
+// TestReconcileRolloutAnalysis tests a ring rolled back when the metrics of the rolled out branch breach a threshold
+func TestReconcileRolloutAnalysis(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	// Stub a Prometheus HTTP API answering a low success rate for the canary branch
+	queries := []string{}
+	prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		query := req.URL.Query().Get("query")
+		queries = append(queries, query)
+
+		value := "0.5"
+		if strings.HasPrefix(query, "histogram_quantile") {
+			value = "0.1"
+		}
+		fmt.Fprintf(w, `{"status":"success","data":{"resultType":"vector","result":[{"metric":{},"value":[1565000000,"%s"]}]}}`, value)
+	}))
+	defer prom.Close()
+
+	metrics, err := ring.NewPrometheusProvider(prom.URL)
+	require.NoError(t, err)
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "master"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	group := "*"
+	weight := 10
+	minSuccessRate := 0.99
+
+	instance := createRing(name, namespace, group, true, selector)
+	instance.Spec.Rollout = &ringsv1alpha1.RingRollout{
+		Branch: "canary",
+		Steps: []ringsv1alpha1.RingRolloutStep{
+			{Weight: &weight, Bake: metav1.Duration{Duration: time.Hour}},
+		},
+		Analysis: &ringsv1alpha1.RingAnalysis{
+			MinSuccessRate: &minSuccessRate,
+			MaxLatency:     &metav1.Duration{Duration: time.Second},
+		},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Metrics: metrics}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure the metrics of the canary branch were queried
+	require.Len(t, queries, 2)
+	require.Contains(t, queries[0], `branch="canary",service="query",version="v1"`)
+
+	// Ensure the rollout was rolled back with the measured values
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	require.Equal(t, ringsv1alpha1.RolloutRolledBack, found.Status.Rollout.Phase)
+	require.NotNil(t, found.Status.Rollout.Analysis)
+	require.Equal(t, ringsv1alpha1.AnalysisFailed, found.Status.Rollout.Analysis.Result)
+	require.Equal(t, 0.5, *found.Status.Rollout.Analysis.SuccessRate)
+	require.Equal(t, 100*time.Millisecond, found.Status.Rollout.Analysis.Latency.Duration)
+
+	// Ensure the ring is routed as if it had no rollout
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, name, ing.Spec.Routes[0].Services[0].Name)
+	require.Empty(t, ing.Spec.Routes[0].Services[0].Kind)
+}
+
+// TestPrometheusProviderScalar tests the metrics queries answered with a scalar rather than a vector
+func TestPrometheusProviderScalar(t *testing.T) {
+	prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		fmt.Fprint(w, `{"status":"success","data":{"resultType":"scalar","result":[1565000000,"0.25"]}}`)
+	}))
+	defer prom.Close()
+
+	metrics, err := ring.NewPrometheusProvider(prom.URL)
+	require.NoError(t, err)
+
+	rate, err := metrics.SuccessRate(map[string]string{"service": "query"}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 0.25, *rate)
+
+	latency, err := metrics.Latency(map[string]string{"service": "query"}, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 250*time.Millisecond, *latency)
+}
+
+// TestReconcileRolloutAnalysisUnavailable tests a rollout held on its step, while the ring stays routed, when its
+// analysis can't query the metrics of the rolled out branch
+func TestReconcileRolloutAnalysisUnavailable(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	// Stub a Prometheus HTTP API which is down
+	queries := 0
+	prom := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		queries++
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer prom.Close()
+
+	metrics, err := ring.NewPrometheusProvider(prom.URL)
+	require.NoError(t, err)
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "master"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	first, second := 10, 50
+	minSuccessRate := 0.99
+
+	// The first step has baked and would move on with a conclusive analysis
+	instance := createRing(name, namespace, "*", true, selector)
+	instance.Spec.Rollout = &ringsv1alpha1.RingRollout{
+		Branch: "canary",
+		Steps: []ringsv1alpha1.RingRolloutStep{
+			{Weight: &first},
+			{Weight: &second, Bake: metav1.Duration{Duration: time.Hour}},
+		},
+		Analysis: &ringsv1alpha1.RingAnalysis{MinSuccessRate: &minSuccessRate},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+	getAnalysisCondition := func(found *ringsv1alpha1.Ring) *ringsv1alpha1.RingCondition {
+		for i := range found.Status.Conditions {
+			if found.Status.Conditions[i].Type == ringsv1alpha1.RingAnalysisReady {
+				return &found.Status.Conditions[i]
+			}
+		}
+		return nil
+	}
+
+	// Neither a failing provider nor a missing one stops the reconciliation
+	for reason, r := range map[string]*ring.ReconcileRing{
+		"MetricsQueryFailed":     {Client: cl, Scheme: s, Metrics: metrics},
+		"MetricsProviderMissing": {Client: cl, Scheme: s},
+	} {
+		result, err := r.Reconcile(req)
+		require.NoError(t, err)
+		require.Equal(t, time.Minute, result.RequeueAfter)
+
+		// Ensure the rollout holds its first step with an inconclusive analysis
+		found := &ringsv1alpha1.Ring{}
+		err = cl.Get(context.TODO(), req.NamespacedName, found)
+		require.NoError(t, err)
+		require.Equal(t, ringsv1alpha1.RolloutProgressing, found.Status.Rollout.Phase)
+		require.Equal(t, 0, found.Status.Rollout.Step)
+		require.Equal(t, ringsv1alpha1.AnalysisInconclusive, found.Status.Rollout.Analysis.Result)
+		cond := getAnalysisCondition(found)
+		require.NotNil(t, cond)
+		require.Equal(t, corev1.ConditionFalse, cond.Status)
+		require.Equal(t, reason, cond.Reason)
+
+		// Ensure the ring is still routed with the weights of its first step
+		ing := &traefik.IngressRoute{}
+		err = cl.Get(context.TODO(), req.NamespacedName, ing)
+		require.NoError(t, err)
+		require.Equal(t, "TraefikService", ing.Spec.Routes[0].Services[0].Kind)
+		ts := &traefik.TraefikService{}
+		err = cl.Get(context.TODO(), types.NamespacedName{Name: ing.Spec.Routes[0].Services[0].Name, Namespace: namespace}, ts)
+		require.NoError(t, err)
+		require.Equal(t, first, *ts.Spec.Weighted.Services[1].Weight)
+
+		// Ensure the analysis is not run again before its interval has passed
+		queried := queries
+		result, err = r.Reconcile(req)
+		require.NoError(t, err)
+		require.True(t, result.RequeueAfter > 0 && result.RequeueAfter <= time.Minute)
+		require.Equal(t, queried, queries)
+		err = cl.Get(context.TODO(), req.NamespacedName, found)
+		require.NoError(t, err)
+		require.Equal(t, reason, getAnalysisCondition(found).Reason)
+
+		// The next analysis is due
+		found.Status.Rollout.Analysis.Time = metav1.NewTime(time.Now().Add(-time.Minute))
+		err = cl.Update(context.TODO(), found)
+		require.NoError(t, err)
+	}
+}
+
+// TestReconcileGroups tests a ring targeting several groups
+func TestReconcileGroups(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	expectedPath := fmt.Sprintf("/%s/%s", selector["service"], selector["version"])
+	expectedRoute := fmt.Sprintf("PathPrefix(`%s`) && (Headers(`group`, `canary`) || Headers(`group`, `dogfood`))", expectedPath)
+
+	// The single group of older rings is kept alongside the groups list
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.Groups = []ringsv1alpha1.RingGroup{
+		{Name: "canary"},
+		{Name: "dogfood"},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure IngressRoute matches a member of any group
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, expectedRoute, ing.Spec.Routes[0].Match)
+}
+
+// TestReconcileMatchers tests a ring routing requests on matchers in addition to its group
+func TestReconcileMatchers(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	expectedRoute := "PathPrefix(`/query/v1`) && (Headers(`group`, `canary`) || " +
+		"HeadersRegexp(`Cookie`, `(^|;\\s*)ring=canary(;|$)`) || " +
+		"Query(`ring=canary`) || " +
+		"ClientIP(`10.0.0.0/8`) || " +
+		"HeadersRegexp(`User-Agent`, `^Preview/.*`))"
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.Matchers = []ringsv1alpha1.RingMatcher{
+		{Type: ringsv1alpha1.MatchCookie, Name: "ring", Value: "canary"},
+		{Type: ringsv1alpha1.MatchQuery, Name: "ring", Value: "canary"},
+		{Type: ringsv1alpha1.MatchClientIP, Value: "10.0.0.0/8"},
+		{Type: ringsv1alpha1.MatchHeaderRegex, Name: "User-Agent", Value: "^Preview/.*"},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure IngressRoute matches the group or any matcher
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, expectedRoute, ing.Spec.Routes[0].Match)
+}
+
+func TestReconcileHosts(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	expectedRoute := "(Host(`api.example.com`) || HostRegexp(`{subdomain:[a-zA-Z0-9-]+}.preview.example.com`)) && " +
+		"PathPrefix(`/query/v1`) && Headers(`group`, `canary`)"
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	instance.Spec.Routing.Hosts = []string{"api.example.com", "*.preview.example.com"}
+
+	// A newer ring claiming the same host, path and group
+	conflictName := name + "-copy"
+	conflict := createRing(conflictName, namespace, "canary", true, selector)
+	conflict.CreationTimestamp = metav1.Now()
+	conflict.Spec.Routing.Hosts = []string{"API.example.com"}
+
+	objs := []runtime.Object{
+		instance,
+		conflict,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure IngressRoute matches the hosts
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, expectedRoute, ing.Spec.Routes[0].Match)
+
+	// Ensure the newer ring is refused its route
+	conflictReq := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      conflictName,
+			Namespace: namespace,
+		},
+	}
+	_, err = r.Reconcile(conflictReq)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), conflictReq.NamespacedName, &traefik.IngressRoute{})
+	require.True(t, errors.IsNotFound(err))
+
+	requireConflict := func() *ringsv1alpha1.Ring {
+		updated := &ringsv1alpha1.Ring{}
+		err := cl.Get(context.TODO(), conflictReq.NamespacedName, updated)
+		require.NoError(t, err)
+		for _, c := range updated.Status.Conditions {
+			if c.Type == ringsv1alpha1.RingRoutingConfigured {
+				require.Equal(t, "RouteConflict", c.Reason)
+				require.Contains(t, c.Message, name)
+			}
+		}
+		return updated
+	}
+	updated := requireConflict()
+
+	// Moving the newer ring to another host clears the conflict
+	updated.Spec.Routing.Hosts = []string{"other.example.com"}
+	err = cl.Update(context.TODO(), updated)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(conflictReq)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), conflictReq.NamespacedName, &traefik.IngressRoute{})
+	require.NoError(t, err)
+
+	// Moving it back withdraws the route it took in the meantime
+	err = cl.Get(context.TODO(), conflictReq.NamespacedName, updated)
+	require.NoError(t, err)
+	updated.Spec.Routing.Hosts = instance.Spec.Routing.Hosts
+	err = cl.Update(context.TODO(), updated)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(conflictReq)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), conflictReq.NamespacedName, &traefik.IngressRoute{})
+	require.True(t, errors.IsNotFound(err))
+	requireConflict()
+
+	// A host matched by the wildcard of the older ring conflicts, as does a wildcard matching its host
+	for _, hosts := range [][]string{{"Beta.preview.example.com"}, {"*.example.com"}} {
+		updated = &ringsv1alpha1.Ring{}
+		err = cl.Get(context.TODO(), conflictReq.NamespacedName, updated)
+		require.NoError(t, err)
+		updated.Spec.Routing.Hosts = hosts
+		err = cl.Update(context.TODO(), updated)
+		require.NoError(t, err)
+
+		_, err = r.Reconcile(conflictReq)
+		require.NoError(t, err)
+		err = cl.Get(context.TODO(), conflictReq.NamespacedName, &traefik.IngressRoute{})
+		require.True(t, errors.IsNotFound(err))
+		requireConflict()
+	}
+
+	// The wildcard doesn't match the domain it is a subdomain of
+	err = cl.Get(context.TODO(), conflictReq.NamespacedName, updated)
+	require.NoError(t, err)
+	updated.Spec.Routing.Hosts = []string{"preview.example.com"}
+	err = cl.Update(context.TODO(), updated)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(conflictReq)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), conflictReq.NamespacedName, &traefik.IngressRoute{})
+	require.NoError(t, err)
+}
+
+func TestReconcilePath(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.Path = &ringsv1alpha1.RingPath{
+		Template:    "/api/{service}",
+		Rewrite:     ringsv1alpha1.RewriteReplace,
+		Replacement: "/v1$1",
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure the path is replaced instead of stripped
+	replaceName := types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-replacepath", name)}
+	m := &traefik.Middleware{}
+	err = cl.Get(context.TODO(), replaceName, m)
+	require.NoError(t, err)
+	require.Equal(t, "^/api/query(.*)", m.Spec.ReplacePathRegex.Regex)
+	require.Equal(t, "/v1$1", m.Spec.ReplacePathRegex.Replacement)
+
+	err = cl.Get(context.TODO(), types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-stripprefix", name)}, &traefik.Middleware{})
+	require.True(t, errors.IsNotFound(err))
+
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, "PathPrefix(`/api/query`) && Headers(`group`, `canary`)", ing.Spec.Routes[0].Match)
+	require.Equal(t, replaceName.Name, ing.Spec.Routes[0].Middlewares[0].Name)
+
+	// Disabling path routing routes the ring on its hosts and groups only
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.Hosts = []string{"query.example.com"}
+	found.Spec.Routing.Path = &ringsv1alpha1.RingPath{Disabled: true}
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	ing = &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, "Host(`query.example.com`) && Headers(`group`, `canary`)", ing.Spec.Routes[0].Match)
+	require.Empty(t, ing.Spec.Routes[0].Middlewares)
+
+	err = cl.Get(context.TODO(), replaceName, &traefik.Middleware{})
+	require.True(t, errors.IsNotFound(err))
+}
+
+func TestReconcileEntryPoints(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	os.Setenv("RING_ENTRYPOINTS", "web, websecure")
+	defer os.Unsetenv("RING_ENTRYPOINTS")
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	objs := []runtime.Object{
+		createRing(name, namespace, "canary", true, selector),
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure the operator default is used
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, []string{"web", "websecure"}, ing.Spec.EntryPoints)
+
+	// Ensure the entrypoints of the ring are applied to the existing IngressRoute
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.EntryPoints = []string{"internal"}
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	ing = &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, []string{"internal"}, ing.Spec.EntryPoints)
+}
+
+func TestReconcileTLS(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	os.Setenv("RING_CERT_MANAGER_ENABLED", "true")
+	defer os.Unsetenv("RING_CERT_MANAGER_ENABLED")
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.Hosts = []string{"query.example.com"}
+	instance.Spec.Routing.TLS = &ringsv1alpha1.RingTLS{
+		Options:     &ringsv1alpha1.RingTLSOptionRef{Name: "modern"},
+		Certificate: &ringsv1alpha1.RingCertificate{IssuerName: "letsencrypt", IssuerKind: "ClusterIssuer"},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	s.AddKnownTypes(certmanager.SchemeGroupVersion, &certmanager.Certificate{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure the Certificate is requested for the ring hosts
+	cert := &certmanager.Certificate{}
+	err = cl.Get(context.TODO(), req.NamespacedName, cert)
+	require.NoError(t, err)
+	require.Equal(t, []string{"query.example.com"}, cert.Spec.DNSNames)
+	require.Equal(t, fmt.Sprintf("%s-tls", name), cert.Spec.SecretName)
+	require.Equal(t, "ClusterIssuer", cert.Spec.IssuerRef.Kind)
+
+	// Ensure the IngressRoute serves the certificate
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, fmt.Sprintf("%s-tls", name), ing.Spec.TLS.SecretName)
+	require.Equal(t, "modern", ing.Spec.TLS.Options.Name)
+
+	requireCondition := func(status corev1.ConditionStatus) {
+		found := &ringsv1alpha1.Ring{}
+		err := cl.Get(context.TODO(), req.NamespacedName, found)
+		require.NoError(t, err)
+		require.Equal(t, name, found.Status.CertificateName)
+		for _, c := range found.Status.Conditions {
+			if c.Type == ringsv1alpha1.RingCertificateReady {
+				require.Equal(t, status, c.Status)
+				return
+			}
+		}
+		t.Fatal("CertificateReady condition not set")
+	}
+	requireCondition(corev1.ConditionFalse)
+
+	// Ensure readiness follows the Certificate once issued
+	cert.Status.Conditions = []certmanager.CertificateCondition{
+		{Type: certmanager.CertificateConditionReady, Status: corev1.ConditionTrue, Reason: "Ready"},
+	}
+	err = cl.Update(context.TODO(), cert)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	requireCondition(corev1.ConditionTrue)
+
+	// Ensure the Certificate is removed along with the TLS block
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.TLS = nil
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), req.NamespacedName, &certmanager.Certificate{})
+	require.True(t, errors.IsNotFound(err))
+
+	ing = &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Nil(t, ing.Spec.TLS)
+
+	// A Certificate of the same name the ring doesn't control is left unchanged
+	foreign := &certmanager.Certificate{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: certmanager.CertificateSpec{
+			SecretName: "foreign-tls",
+			DNSNames:   []string{"foreign.example.com"},
+			IssuerRef:  certmanager.ObjectReference{Name: "foreign", Kind: "Issuer"},
+		},
+	}
+	err = cl.Create(context.TODO(), foreign)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.TLS = instance.Spec.Routing.TLS
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.True(t, errors.IsConflict(err))
+	cert = &certmanager.Certificate{}
+	err = cl.Get(context.TODO(), req.NamespacedName, cert)
+	require.NoError(t, err)
+	require.Equal(t, foreign.Spec, cert.Spec)
+	require.Empty(t, cert.OwnerReferences)
+
+	// Nor is it deleted along with the TLS block
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.TLS = nil
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &certmanager.Certificate{})
+	require.NoError(t, err)
+	err = cl.Delete(context.TODO(), foreign)
+	require.NoError(t, err)
+
+	// A certificate requested while cert-manager is disabled is reported as invalid without being requeued
+	os.Unsetenv("RING_CERT_MANAGER_ENABLED")
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.TLS = instance.Spec.Routing.TLS
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	result, err := r.Reconcile(req)
+	require.NoError(t, err)
+	require.False(t, result.Requeue)
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	invalid := false
+	for _, c := range found.Status.Conditions {
+		if c.Type == ringsv1alpha1.RingRoutingConfigured {
+			require.Equal(t, corev1.ConditionFalse, c.Status)
+			require.Equal(t, "Invalid", c.Reason)
+			require.Contains(t, c.Message, "RING_CERT_MANAGER_ENABLED")
+			invalid = true
+		}
+	}
+	require.True(t, invalid)
+}
+
+func TestReconcileRateLimit(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	burst := int64(50)
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.RateLimit = &ringsv1alpha1.RingRateLimit{
+		Average: 100,
+		Period:  &metav1.Duration{Duration: time.Minute},
+		Burst:   &burst,
+		Source:  &ringsv1alpha1.RingRateLimitSource{IPDepth: 1},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure the RateLimit Middleware reflects the ring spec
+	rateLimitName := types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-ratelimit", name)}
+	m := &traefik.Middleware{}
+	err = cl.Get(context.TODO(), rateLimitName, m)
+	require.NoError(t, err)
+	require.Equal(t, int64(100), m.Spec.RateLimit.Average)
+	require.Equal(t, "1m0s", m.Spec.RateLimit.Period)
+	require.Equal(t, burst, *m.Spec.RateLimit.Burst)
+	require.Equal(t, 1, m.Spec.RateLimit.SourceCriterion.IPStrategy.Depth)
+
+	// Ensure the rate limit is applied before the prefix is stripped
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Len(t, ing.Spec.Routes[0].Middlewares, 2)
+	require.Equal(t, rateLimitName.Name, ing.Spec.Routes[0].Middlewares[0].Name)
+	require.Equal(t, fmt.Sprintf("%s-stripprefix", name), ing.Spec.Routes[0].Middlewares[1].Name)
+
+	// Ensure removing the spec removes the Middleware
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.RateLimit = nil
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), rateLimitName, &traefik.Middleware{})
+	require.True(t, errors.IsNotFound(err))
+
+	ing = &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Len(t, ing.Spec.Routes[0].Middlewares, 1)
+}
+
+func TestReconcileMiddlewares(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.Middlewares = []ringsv1alpha1.RingMiddleware{
+		{Name: "oauth", Namespace: "auth"},
+		{Name: "compress", Spec: &runtime.RawExtension{Raw: []byte(`{"compress":{}}`)}},
+		{Name: "retry"},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	recorder := record.NewFakeRecorder(10)
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Recorder: recorder}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure only the inline Middleware is created
+	compressName := types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-compress", name)}
+	m := &traefik.Middleware{}
+	err = cl.Get(context.TODO(), compressName, m)
+	require.NoError(t, err)
+	require.NotNil(t, m.Spec.Compress)
+
+	// Ensure the chain keeps the order of the ring
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, []traefik.MiddlewareRef{
+		{Name: "oauth", Namespace: "auth"},
+		{Name: compressName.Name, Namespace: namespace},
+		{Name: "retry", Namespace: namespace},
+		{Name: fmt.Sprintf("%s-stripprefix", name), Namespace: namespace},
+	}, ing.Spec.Routes[0].Middlewares)
+
+	// Ensure an inline spec must set a single middleware
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.Middlewares = []ringsv1alpha1.RingMiddleware{
+		{Name: "both", Spec: &runtime.RawExtension{Raw: []byte(`{"compress":{},"retry":{"attempts":3}}`)}},
+	}
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	// The invalid ring is reported without being requeued
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	for _, c := range found.Status.Conditions {
+		if c.Type == ringsv1alpha1.RingRoutingConfigured || c.Type == ringsv1alpha1.RingReady {
+			require.Equal(t, corev1.ConditionFalse, c.Status)
+			require.Equal(t, "Invalid", c.Reason)
+		}
+	}
+	events := []string{}
+	for len(recorder.Events) > 0 {
+		events = append(events, <-recorder.Events)
+	}
+	require.Contains(t, events[len(events)-1], "Warning Invalid")
+
+	// Ensure an inline spec with fields Traefik Middlewares don't have is refused
+	found.Spec.Routing.Middlewares = []ringsv1alpha1.RingMiddleware{
+		{Name: "chain", Spec: &runtime.RawExtension{Raw: []byte(`{"chain":{"middlewares":[{"name":"a"}]}}`)}},
+	}
+	router, err := ring.NewRouter("traefik")
+	require.NoError(t, err)
+	errs := ring.ValidateRing(found, router)
+	require.Len(t, errs, 1)
+	require.Contains(t, errs[0].Error(), `unknown field "chain"`)
+
+	// Ensure removing the inline spec removes its Middleware
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	found.Spec.Routing.Middlewares = nil
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), compressName, &traefik.Middleware{})
+	require.True(t, errors.IsNotFound(err))
+}
+
+func TestReconcileStampHeaders(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	os.Setenv("RING_STAMP_HEADERS", "true")
+	defer os.Unsetenv("RING_STAMP_HEADERS")
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	objs := []runtime.Object{
+		createRing(name, namespace, "canary", true, selector),
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure requests and responses are stamped with the ring
+	stampName := types.NamespacedName{Namespace: namespace, Name: fmt.Sprintf("%s-stamp", name)}
+	m := &traefik.Middleware{}
+	err = cl.Get(context.TODO(), stampName, m)
+	require.NoError(t, err)
+	expected := map[string]string{"X-Ring-Name": name, "X-Ring-Version": "v1", "X-Ring-Branch": "canary"}
+	require.Equal(t, expected, m.Spec.Headers.CustomRequestHeaders)
+	require.Equal(t, expected, m.Spec.Headers.CustomResponseHeaders)
+
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, stampName.Name, ing.Spec.Routes[0].Middlewares[0].Name)
+
+	// Ensure a ring can opt out of the operator default
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, found)
+	require.NoError(t, err)
+	stamp := false
+	found.Spec.Routing.StampHeaders = &stamp
+	err = cl.Update(context.TODO(), found)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), stampName, &traefik.Middleware{})
+	require.True(t, errors.IsNotFound(err))
+}
+
+// TestReconcileDefaults tests the defaults of a ring admitted without the mutating webhook are applied
+func TestReconcileDefaults(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+	expectedRoute := fmt.Sprintf("PathPrefix(`/%s/%s`) && Headers(`group`, `dogfood`)", selector["service"], selector["version"])
+
+	os.Setenv("RING_DEFAULT_GROUP", "dogfood")
+	defer os.Unsetenv("RING_DEFAULT_GROUP")
+
+	// The branch comes from the ring label and the group from the operator default
+	instance := createRing(name, namespace, "", true, selector)
+	instance.Labels = map[string]string{"branch": selector["branch"]}
+	instance.Spec.Routing.Branch = ""
+	instance.Spec.Routing.Ports[0].Name = ""
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	// Reconcile request
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+
+	// Ensure the Service selects the branch of the label with the defaulted port
+	svc := &corev1.Service{}
+	err = cl.Get(context.TODO(), req.NamespacedName, svc)
+	require.NoError(t, err)
+	require.Equal(t, selector, svc.Spec.Selector)
+	require.Equal(t, []corev1.ServicePort{{
+		Name:       "default",
+		Protocol:   corev1.ProtocolTCP,
+		Port:       80,
+		TargetPort: intstr.FromInt(80),
+	}}, svc.Spec.Ports)
+
+	// Ensure IngressRoute is routed to the default group
+	ing := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, expectedRoute, ing.Spec.Routes[0].Match)
+}
+
+// TestReconcileWorkloads tests the target ports of a ring are verified against its Deployments
+func TestReconcileWorkloads(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.Ports[0].TargetPort = intstr.FromInt(8080)
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	recorder := record.NewFakeRecorder(10)
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Recorder: recorder}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	getCondition := func() *ringsv1alpha1.RingCondition {
+		instance := &ringsv1alpha1.Ring{}
+		err := cl.Get(context.TODO(), req.NamespacedName, instance)
+		require.NoError(t, err)
+		for _, c := range instance.Status.Conditions {
+			if c.Type == ringsv1alpha1.RingWorkloadVerified {
+				return &c
+			}
+		}
+		return nil
+	}
+
+	// The Deployment exposes the target port by number
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+	cond := getCondition()
+	require.NotNil(t, cond)
+	require.Equal(t, corev1.ConditionTrue, cond.Status)
+	require.Empty(t, recorder.Events)
+
+	// A target port the Deployment doesn't expose is reported once
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Routing.Ports[0].TargetPort = intstr.FromString("web")
+	instance.Spec.Routing.Split = []ringsv1alpha1.RingBranchWeight{
+		{Branch: "canary", Weight: 90},
+		{Branch: "beta", Weight: 10},
+	}
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	cond = getCondition()
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Equal(t, "port web not exposed by deployment "+name+", no workload found for service query version v1 branch beta", cond.Message)
+	require.Len(t, recorder.Events, 1)
+	require.Contains(t, <-recorder.Events, "Warning WorkloadNotFound port web not exposed")
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	require.Empty(t, recorder.Events)
+}
+
+// TestReconcileReadyEndpoints tests a ring requiring ready endpoints is only routed while its Service has some
+func TestReconcileReadyEndpoints(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	ready := true
+	instance.Spec.Routing.RequireReadyEndpoints = &ready
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.Middleware{}, &traefik.MiddlewareList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.TraefikService{}, &traefik.TraefikServiceList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	getCondition := func(conditionType ringsv1alpha1.RingConditionType) *ringsv1alpha1.RingCondition {
+		instance := &ringsv1alpha1.Ring{}
+		err := cl.Get(context.TODO(), req.NamespacedName, instance)
+		require.NoError(t, err)
+		for _, c := range instance.Status.Conditions {
+			if c.Type == conditionType {
+				return &c
+			}
+		}
+		return nil
+	}
+
+	// Without endpoints the Service is created but the ring is not routed
+	_, err := r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &corev1.Service{})
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.True(t, errors.IsNotFound(err))
+	cond := getCondition(ringsv1alpha1.RingEndpointsReady)
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Equal(t, "no ready endpoints for branch canary", cond.Message)
+	require.Equal(t, "WaitingForEndpoints", getCondition(ringsv1alpha1.RingReady).Reason)
+
+	// Once the Service has a ready address the ring is routed
+	endpoints := &corev1.Endpoints{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Subsets: []corev1.EndpointSubset{
+			{Addresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+		},
+	}
+	err = cl.Create(context.TODO(), endpoints)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionTrue, getCondition(ringsv1alpha1.RingEndpointsReady).Status)
+	require.Equal(t, corev1.ConditionTrue, getCondition(ringsv1alpha1.RingReady).Status)
+
+	// When the address is no longer ready the requests fall back to the production ring
+	endpoints.Subsets = []corev1.EndpointSubset{
+		{NotReadyAddresses: []corev1.EndpointAddress{{IP: "10.0.0.1"}}},
+	}
+	err = cl.Update(context.TODO(), endpoints)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.True(t, errors.IsNotFound(err))
+	require.Equal(t, corev1.ConditionFalse, getCondition(ringsv1alpha1.RingEndpointsReady).Status)
+}
+
+// configMapRouter routes a ring with a ConfigMap holding its match, standing in for another ingress controller
+type configMapRouter struct{}
+
+func (c *configMapRouter) Name() string                                 { return "configmap" }
+func (c *configMapRouter) AddToScheme(s *runtime.Scheme) error          { return nil }
+func (c *configMapRouter) Validate(*ringsv1alpha1.Ring) field.ErrorList { return nil }
+
+func (c *configMapRouter) OwnedTypes() []ring.OwnedType {
+	return []ring.OwnedType{{Object: &corev1.ConfigMap{}, List: &corev1.ConfigMapList{}}}
+}
+
+func (c *configMapRouter) Route(cr *ringsv1alpha1.Ring) (*ring.Routing, error) {
+	match := fmt.Sprintf("/%s/%s", cr.Spec.Routing.Service, cr.Spec.Routing.Version)
+	return &ring.Routing{
+		Route: &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{Name: cr.Name, Namespace: cr.Namespace},
+			Data:       map[string]string{"match": match},
+		},
+		Match: match,
+	}, nil
+}
+
+// TestReconcileRouter tests the routing objects of a ring are produced by the router of the reconciler
+func TestReconcileRouter(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	_, err := ring.NewRouter("haproxy")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `unknown router "haproxy", expected one of`)
+	router, err := ring.NewRouter("traefik")
+	require.NoError(t, err)
+	require.Equal(t, "traefik", router.Name())
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	objs := []runtime.Object{
+		createRing(name, namespace, "canary", true, selector),
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(traefik.SchemeGroupVersion, &traefik.IngressRoute{}, &traefik.IngressRouteList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Router: &configMapRouter{}}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	// The ring is routed by the ConfigMap rather than an IngressRoute
+	cm := &corev1.ConfigMap{}
+	err = cl.Get(context.TODO(), req.NamespacedName, cm)
+	require.NoError(t, err)
+	require.Equal(t, "/query/v1", cm.Data["match"])
+	err = cl.Get(context.TODO(), req.NamespacedName, &traefik.IngressRoute{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), req.NamespacedName, &corev1.Service{})
+	require.NoError(t, err)
+
+	instance := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), req.NamespacedName, instance)
+	require.NoError(t, err)
+	require.Equal(t, name, instance.Status.IngressRouteName)
+	require.Equal(t, "/query/v1", instance.Status.Match)
+
+	// The routing objects of the router are removed once the ring is on standby
+	instance.Spec.State = ringsv1alpha1.RingStandby
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), req.NamespacedName, &corev1.ConfigMap{})
+	require.True(t, errors.IsNotFound(err))
+}
+
+// TestReconcileGateway tests the gateway router routes a ring with an HTTPRoute and reports whether it was accepted
+func TestReconcileGateway(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	_, err := ring.NewRouter("gateway")
+	require.Error(t, err)
+
+	os.Setenv("RING_GATEWAY", "infra/public")
+	defer os.Unsetenv("RING_GATEWAY")
+	router, err := ring.NewRouter("gateway")
+	require.NoError(t, err)
+
+	namespace := "default"
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	name := fmt.Sprintf("%s-%s-%s", selector["service"], selector["version"], selector["branch"])
+
+	instance := createRing(name, namespace, "canary", true, selector)
+	instance.Spec.Routing.Hosts = []string{"api.example.com"}
+	instance.Spec.Routing.Matchers = []ringsv1alpha1.RingMatcher{
+		{Type: ringsv1alpha1.MatchQuery, Name: "preview", Value: "true"},
+	}
+	objs := []runtime.Object{
+		instance,
+		createDeployment(name, namespace, selector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(gatewayv1.SchemeGroupVersion, &gatewayv1.HTTPRoute{}, &gatewayv1.HTTPRouteList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Router: router}
+	req := reconcile.Request{
+		NamespacedName: types.NamespacedName{
+			Name:      name,
+			Namespace: namespace,
+		},
+	}
+
+	getCondition := func(conditionType ringsv1alpha1.RingConditionType) *ringsv1alpha1.RingCondition {
+		instance := &ringsv1alpha1.Ring{}
+		err := cl.Get(context.TODO(), req.NamespacedName, instance)
+		require.NoError(t, err)
+		for _, c := range instance.Status.Conditions {
+			if c.Type == conditionType {
+				return &c
+			}
+		}
+		return nil
+	}
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+
+	// The HTTPRoute attaches to the Gateway and matches the group and the matcher of the ring on its path
+	route := &gatewayv1.HTTPRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, route)
+	require.NoError(t, err)
+	require.Len(t, route.Spec.ParentRefs, 1)
+	require.Equal(t, "public", route.Spec.ParentRefs[0].Name)
+	require.Equal(t, "infra", *route.Spec.ParentRefs[0].Namespace)
+	require.Equal(t, []string{"api.example.com"}, route.Spec.Hostnames)
+
+	rule := route.Spec.Rules[0]
+	require.Len(t, rule.Matches, 2)
+	for _, m := range rule.Matches {
+		require.Equal(t, gatewayv1.PathMatchPathPrefix, *m.Path.Type)
+		require.Equal(t, "/query/v1", *m.Path.Value)
+	}
+	require.Equal(t, "group", rule.Matches[0].Headers[0].Name)
+	require.Equal(t, "canary", rule.Matches[0].Headers[0].Value)
+	require.Equal(t, "preview", rule.Matches[1].QueryParams[0].Name)
+
+	require.Len(t, rule.Filters, 1)
+	require.Equal(t, gatewayv1.HTTPRouteFilterURLRewrite, rule.Filters[0].Type)
+	require.Equal(t, "/", *rule.Filters[0].URLRewrite.Path.ReplacePrefixMatch)
+	require.Len(t, rule.BackendRefs, 1)
+	require.Equal(t, name, rule.BackendRefs[0].Name)
+	require.Equal(t, int32(80), *rule.BackendRefs[0].Port)
+
+	require.Equal(t, corev1.ConditionUnknown, getCondition(ringsv1alpha1.RingRouteAccepted).Status)
+
+	// The status written by the Gateway is kept and reported on the ring
+	route.Status.Parents = []gatewayv1.RouteParentStatus{{
+		ParentRef:      route.Spec.ParentRefs[0],
+		ControllerName: "example.com/gateway-controller",
+		Conditions:     []gatewayv1.Condition{{Type: "Accepted", Status: "True", Reason: "Accepted"}},
+	}}
+	err = cl.Update(context.TODO(), route)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	require.Equal(t, corev1.ConditionTrue, getCondition(ringsv1alpha1.RingRouteAccepted).Status)
+	require.Equal(t, corev1.ConditionTrue, getCondition(ringsv1alpha1.RingReady).Status)
+
+	route = &gatewayv1.HTTPRoute{}
+	err = cl.Get(context.TODO(), req.NamespacedName, route)
+	require.NoError(t, err)
+	route.Status.Parents[0].Conditions[0].Status = "False"
+	route.Status.Parents[0].Conditions[0].Reason = "NotAllowedByListeners"
+	route.Status.Parents[0].Conditions[0].Message = "namespace default is not allowed"
+	err = cl.Update(context.TODO(), route)
+	require.NoError(t, err)
+
+	_, err = r.Reconcile(req)
+	require.NoError(t, err)
+	cond := getCondition(ringsv1alpha1.RingRouteAccepted)
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Equal(t, "NotAllowedByListeners", cond.Reason)
+	require.Equal(t, "gateway infra/public: namespace default is not allowed", cond.Message)
+	require.Equal(t, "RouteNotAccepted", getCondition(ringsv1alpha1.RingReady).Reason)
+
+	// Every weighted branch stamps its own branch, the rule stamps the headers shared by the branches
+	stamp := true
+	split := instance.DeepCopy()
+	split.Spec.Routing.StampHeaders = &stamp
+	split.Spec.Routing.Split = []ringsv1alpha1.RingBranchWeight{{Branch: "canary", Weight: 90}, {Branch: "next", Weight: 10}}
+	routing, err := router.Route(split)
+	require.NoError(t, err)
+	rule = routing.Route.(*gatewayv1.HTTPRoute).Spec.Rules[0]
+	require.Equal(t, gatewayv1.HTTPRouteFilterRequestHeaderModifier, rule.Filters[0].Type)
+	for _, h := range rule.Filters[0].RequestHeaderModifier.Set {
+		require.NotEqual(t, "X-Ring-Branch", h.Name)
+	}
+	require.Len(t, rule.BackendRefs, 2)
+	for i, branch := range []string{"canary", "next"} {
+		filters := rule.BackendRefs[i].Filters
+		require.Len(t, filters, 2)
+		require.Equal(t, []gatewayv1.HTTPHeader{{Name: "X-Ring-Branch", Value: branch}}, filters[0].RequestHeaderModifier.Set)
+		require.Equal(t, []gatewayv1.HTTPHeader{{Name: "X-Ring-Branch", Value: branch}}, filters[1].ResponseHeaderModifier.Set)
+	}
+
+	// Traefik middlewares can't be expressed in an HTTPRoute
+	instance.Spec.Routing.RateLimit = &ringsv1alpha1.RingRateLimit{Average: 10}
+	errs := router.Validate(instance)
+	require.Len(t, errs, 1)
+	require.Equal(t, "spec.routing.rateLimit", errs[0].Field)
+}
+
+func TestReconcileIstio(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	_, err := ring.NewRouter("istio")
+	require.Error(t, err)
+
+	os.Setenv("RING_ISTIO_GATEWAY", "istio-system/ingressgateway")
+	defer os.Unsetenv("RING_ISTIO_GATEWAY")
+	router, err := ring.NewRouter("istio")
+	require.NoError(t, err)
+
+	namespace := "default"
+	canarySelector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	prodSelector := map[string]string{"service": "query", "version": "v1", "branch": "prod"}
+	canaryName, prodName := "query-v1-canary", "query-v1-prod"
+
+	canary := createRing(canaryName, namespace, "canary", true, canarySelector)
+	canary.Spec.Routing.Hosts = []string{"api.example.com"}
+	prod := createRing(prodName, namespace, "*", true, prodSelector)
+	prod.Spec.Routing.Hosts = []string{"api.example.com"}
+	// Both rings name their port default
+	prod.Spec.Routing.Ports[0].Port = 8080
+	prod.Spec.Routing.Ports[0].TargetPort = intstr.FromInt(80)
+	objs := []runtime.Object{
+		canary,
+		prod,
+		createDeployment(canaryName, namespace, canarySelector),
+		createDeployment(prodName, namespace, prodSelector),
+	}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(istio.SchemeGroupVersion, &istio.VirtualService{}, &istio.VirtualServiceList{}, &istio.DestinationRule{}, &istio.DestinationRuleList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Router: router}
+	canaryReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: canaryName, Namespace: namespace}}
+	prodReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: prodName, Namespace: namespace}}
+	sharedName := types.NamespacedName{Name: "query", Namespace: namespace}
+
+	// The rings of the service share a VirtualService, whatever ring is reconciled first
+	_, err = r.Reconcile(canaryReq)
+	require.NoError(t, err)
+
+	vs := &istio.VirtualService{}
+	err = cl.Get(context.TODO(), sharedName, vs)
+	require.NoError(t, err)
+	require.Equal(t, []string{"istio-system/ingressgateway"}, vs.Spec.Gateways)
+	require.Equal(t, []string{"api.example.com"}, vs.Spec.Hosts)
+	require.Len(t, vs.Spec.HTTP, 2)
+	require.Len(t, vs.OwnerReferences, 2)
+
+	// The production ring is the last, catch-all route
+	route := vs.Spec.HTTP[0]
+	require.Equal(t, canaryName, route.Name)
+	require.Len(t, route.Match, 2)
+	require.Equal(t, "/query/v1", route.Match[0].URI.Exact)
+	require.Equal(t, "/query/v1/", route.Match[1].URI.Prefix)
+	require.Equal(t, "api.example.com", route.Match[0].Authority.Exact)
+	require.Equal(t, "canary", route.Match[0].Headers["group"].Exact)
+	require.Equal(t, "/", route.Rewrite.URI)
+	require.Len(t, route.Route, 1)
+	require.Equal(t, "query", route.Route[0].Destination.Host)
+	require.Equal(t, "v1-canary", route.Route[0].Destination.Subset)
+	require.Equal(t, uint32(80), route.Route[0].Destination.Port.Number)
+
+	route = vs.Spec.HTTP[1]
+	require.Equal(t, prodName, route.Name)
+	require.Empty(t, route.Match[0].Headers)
+	require.Equal(t, "v1-prod", route.Route[0].Destination.Subset)
+
+	// The subsets select the branches through a Service of the service
+	dr := &istio.DestinationRule{}
+	err = cl.Get(context.TODO(), sharedName, dr)
+	require.NoError(t, err)
+	require.Equal(t, "query", dr.Spec.Host)
+	require.Len(t, dr.Spec.Subsets, 2)
+	require.Equal(t, "v1-canary", dr.Spec.Subsets[0].Name)
+	require.Equal(t, "canary", dr.Spec.Subsets[0].Labels["branch"])
+
+	svc := &corev1.Service{}
+	err = cl.Get(context.TODO(), sharedName, svc)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"service": "query"}, svc.Spec.Selector)
+	require.Len(t, svc.Spec.Ports, 2)
+	require.Equal(t, "default", svc.Spec.Ports[0].Name)
+	require.Equal(t, int32(80), svc.Spec.Ports[0].Port)
+	require.Equal(t, "default-8080", svc.Spec.Ports[1].Name)
+	require.Equal(t, int32(8080), svc.Spec.Ports[1].Port)
+
+	instance := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, instance)
+	require.NoError(t, err)
+	require.Equal(t, "query", instance.Status.IngressRouteName)
+
+	// A ring on standby leaves the VirtualService
+	instance.Spec.Deploy = false
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(canaryReq)
+	require.NoError(t, err)
+
+	vs = &istio.VirtualService{}
+	err = cl.Get(context.TODO(), sharedName, vs)
+	require.NoError(t, err)
+	require.Len(t, vs.Spec.HTTP, 1)
+	require.Equal(t, prodName, vs.Spec.HTTP[0].Name)
+	require.Len(t, vs.OwnerReferences, 1)
+
+	// The shared objects are removed with the last routed ring
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), prodReq.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Deploy = false
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(prodReq)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), sharedName, &istio.VirtualService{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), sharedName, &istio.DestinationRule{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), sharedName, &corev1.Service{})
+	require.True(t, errors.IsNotFound(err))
+
+	// The Service of the application named after its service is not taken over
+	user := &corev1.Service{
+		ObjectMeta: metav1.ObjectMeta{Name: "query", Namespace: namespace},
+		Spec: corev1.ServiceSpec{
+			Ports:    []corev1.ServicePort{{Name: "http", Port: 9090}},
+			Selector: map[string]string{"app": "query"},
+		},
+	}
+	err = cl.Create(context.TODO(), user)
+	require.NoError(t, err)
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), prodReq.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Deploy = true
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(prodReq)
+	require.True(t, errors.IsConflict(err))
+
+	svc = &corev1.Service{}
+	err = cl.Get(context.TODO(), sharedName, svc)
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"app": "query"}, svc.Spec.Selector)
+	require.Equal(t, []corev1.ServicePort{{Name: "http", Port: 9090}}, svc.Spec.Ports)
+	require.Empty(t, svc.OwnerReferences)
+
+	// Withdrawing the rings leaves the Service of the application in place
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), prodReq.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Deploy = false
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(prodReq)
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), sharedName, &corev1.Service{})
+	require.NoError(t, err)
+
+	// Split weights are scaled to the 100 Istio expects
+	canary.Spec.Routing.Split = []ringsv1alpha1.RingBranchWeight{{Branch: "canary", Weight: 1}, {Branch: "next", Weight: 2}}
+	objs, err = router.(ring.SharedRouter).RouteShared(canary, []*ringsv1alpha1.Ring{canary})
+	require.NoError(t, err)
+	destinations := objs[0].(*istio.VirtualService).Spec.HTTP[0].Route
+	require.Equal(t, int32(33), destinations[0].Weight)
+	require.Equal(t, int32(67), destinations[1].Weight)
+	require.Nil(t, destinations[0].Headers)
+
+	// Every destination of a split stamps its own branch
+	stamp := true
+	canary.Spec.Routing.StampHeaders = &stamp
+	objs, err = router.(ring.SharedRouter).RouteShared(canary, []*ringsv1alpha1.Ring{canary})
+	require.NoError(t, err)
+	route = objs[0].(*istio.VirtualService).Spec.HTTP[0]
+	require.NotContains(t, route.Headers.Request.Set, "X-Ring-Branch")
+	require.Equal(t, map[string]string{"X-Ring-Branch": "next"}, route.Route[1].Headers.Request.Set)
+	require.Equal(t, map[string]string{"X-Ring-Branch": "next"}, route.Route[1].Headers.Response.Set)
+	canary.Spec.Routing.StampHeaders = nil
+
+	// Rate limits are Traefik middlewares
+	canary.Spec.Routing.RateLimit = &ringsv1alpha1.RingRateLimit{Average: 10}
+	errs := router.Validate(canary)
+	require.Len(t, errs, 1)
+	require.Equal(t, "spec.routing.rateLimit", errs[0].Field)
+	canary.Spec.Routing.RateLimit = nil
+
+	// The destinations of a route select a single port
+	canary.Spec.Routing.Ports = append(canary.Spec.Routing.Ports, ringsv1alpha1.RingPort{Name: "metrics", Port: 9090})
+	errs = router.Validate(canary)
+	require.Len(t, errs, 1)
+	require.Equal(t, "spec.routing.ports", errs[0].Field)
+}
+
+func TestReconcileNginx(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	router, err := ring.NewRouter("nginx")
+	require.NoError(t, err)
+
+	namespace := "default"
+	prodSelector := map[string]string{"service": "query", "version": "v1", "branch": "prod"}
+	canarySelector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	betaSelector := map[string]string{"service": "query", "version": "v1", "branch": "beta"}
+	prodName, canaryName, betaName := "query-v1-prod", "query-v1-canary", "query-v1-beta"
+
+	// The beta ring claims the canary Ingress of the host and path after the canary ring
+	prod := createRing(prodName, namespace, "*", true, prodSelector)
+	canary := createRing(canaryName, namespace, "canary", true, canarySelector)
+	canary.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	beta := createRing(betaName, namespace, "beta", true, betaSelector)
+	beta.CreationTimestamp = metav1.NewTime(time.Now())
+	prod.UID, canary.UID, beta.UID = "prod-uid", "canary-uid", "beta-uid"
+	objs := []runtime.Object{prod, canary, beta}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(networkingv1.SchemeGroupVersion, &networkingv1.Ingress{}, &networkingv1.IngressList{})
+	cl := fake.NewFakeClient(objs...)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Router: router}
+	newRequest := func(name string) reconcile.Request {
+		return reconcile.Request{NamespacedName: types.NamespacedName{Name: name, Namespace: namespace}}
+	}
+
+	// The production ring is the primary Ingress, its path prefix is stripped with a rewrite target
+	_, err = r.Reconcile(newRequest(prodName))
+	require.NoError(t, err)
+
+	ing := &networkingv1.Ingress{}
+	err = cl.Get(context.TODO(), newRequest(prodName).NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, "nginx", *ing.Spec.IngressClassName)
+	require.Empty(t, ing.Annotations["nginx.ingress.kubernetes.io/canary"])
+	require.Equal(t, "/$2", ing.Annotations["nginx.ingress.kubernetes.io/rewrite-target"])
+	path := ing.Spec.Rules[0].HTTP.Paths[0]
+	require.Equal(t, "/query/v1(/|$)(.*)", path.Path)
+	require.Equal(t, networkingv1.PathTypeImplementationSpecific, *path.PathType)
+	require.Equal(t, prodName, path.Backend.Service.Name)
+	require.Equal(t, int32(80), path.Backend.Service.Port.Number)
+
+	// Ingresses have no rule to report on the ring
+	instance := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), newRequest(prodName).NamespacedName, instance)
+	require.NoError(t, err)
+	require.Equal(t, prodName, instance.Status.IngressRouteName)
+	require.Empty(t, instance.Status.Match)
+
+	// The other rings are canary Ingresses matching the routing key
+	_, err = r.Reconcile(newRequest(canaryName))
+	require.NoError(t, err)
+
+	ing = &networkingv1.Ingress{}
+	err = cl.Get(context.TODO(), newRequest(canaryName).NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, "true", ing.Annotations["nginx.ingress.kubernetes.io/canary"])
+	require.Equal(t, "group", ing.Annotations["nginx.ingress.kubernetes.io/canary-by-header"])
+	require.Equal(t, "canary", ing.Annotations["nginx.ingress.kubernetes.io/canary-by-header-value"])
+
+	// ingress-nginx routes a single canary per host and path
+	_, err = r.Reconcile(newRequest(betaName))
+	require.NoError(t, err)
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), newRequest(betaName).NamespacedName, instance)
+	require.NoError(t, err)
+	cond := instance.Status.Conditions[0]
+	for _, c := range instance.Status.Conditions {
+		if c.Type == ringsv1alpha1.RingRoutingConfigured {
+			cond = c
+		}
+	}
+	require.Equal(t, corev1.ConditionFalse, cond.Status)
+	require.Equal(t, "RouterLimit", cond.Reason)
+	require.Contains(t, cond.Message, "single canary Ingress per host and path")
+	err = cl.Get(context.TODO(), newRequest(betaName).NamespacedName, &networkingv1.Ingress{})
+	require.True(t, errors.IsNotFound(err))
+
+	// The beta ring takes the canary Ingress while the canary ring is on standby
+	setDeploy := func(name string, deploy bool) {
+		found := &ringsv1alpha1.Ring{}
+		err := cl.Get(context.TODO(), newRequest(name).NamespacedName, found)
+		require.NoError(t, err)
+		found.Spec.Deploy = deploy
+		err = cl.Update(context.TODO(), found)
+		require.NoError(t, err)
+		_, err = r.Reconcile(newRequest(name))
+		require.NoError(t, err)
+	}
+	setDeploy(canaryName, false)
+	_, err = r.Reconcile(newRequest(betaName))
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), newRequest(betaName).NamespacedName, &networkingv1.Ingress{})
+	require.NoError(t, err)
+
+	// The routed beta ring withdraws its Ingress once the canary ring, which claimed it first, is active again
+	setDeploy(canaryName, true)
+	res, err := r.Reconcile(newRequest(betaName))
+	require.NoError(t, err)
+	require.False(t, res.Requeue)
+	err = cl.Get(context.TODO(), newRequest(betaName).NamespacedName, &networkingv1.Ingress{})
+	require.True(t, errors.IsNotFound(err))
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), newRequest(betaName).NamespacedName, instance)
+	require.NoError(t, err)
+	require.Empty(t, instance.Status.IngressRouteName)
+	for _, c := range instance.Status.Conditions {
+		if c.Type == ringsv1alpha1.RingRoutingConfigured || c.Type == ringsv1alpha1.RingReady {
+			require.Equal(t, corev1.ConditionFalse, c.Status)
+			require.Equal(t, "RouterLimit", c.Reason)
+		}
+	}
+	ing = &networkingv1.Ingress{}
+	err = cl.Get(context.TODO(), newRequest(canaryName).NamespacedName, ing)
+	require.NoError(t, err)
+
+	// The canary annotations follow the groups of the ring, the ones set by others are kept
+	ing.Annotations["example.com/owner"] = "team"
+	err = cl.Update(context.TODO(), ing)
+	require.NoError(t, err)
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), newRequest(canaryName).NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Routing.Groups = []ringsv1alpha1.RingGroup{{Name: "canary"}, {Name: "insiders"}}
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(newRequest(canaryName))
+	require.NoError(t, err)
+
+	ing = &networkingv1.Ingress{}
+	err = cl.Get(context.TODO(), newRequest(canaryName).NamespacedName, ing)
+	require.NoError(t, err)
+	require.Equal(t, "^(canary|insiders)$", ing.Annotations["nginx.ingress.kubernetes.io/canary-by-header-pattern"])
+	require.Empty(t, ing.Annotations["nginx.ingress.kubernetes.io/canary-by-header-value"])
+	require.Equal(t, "team", ing.Annotations["example.com/owner"])
+
+	// A canary matches a single matcher
+	canary.Spec.Routing.Groups = nil
+	canary.Spec.Routing.Group = ringsv1alpha1.RingGroup{}
+	canary.Spec.Routing.Matchers = []ringsv1alpha1.RingMatcher{
+		{Type: ringsv1alpha1.MatchHeader, Name: "X-Preview", Value: "true"},
+		{Type: ringsv1alpha1.MatchCookie, Name: "preview", Value: "always"},
+	}
+	errs := router.Validate(canary)
+	require.Len(t, errs, 1)
+	require.Equal(t, "spec.routing.matchers", errs[0].Field)
+
+	// The backend of an Ingress is a single port
+	ports := canary.Spec.Routing.Ports
+	canary.Spec.Routing.Matchers = canary.Spec.Routing.Matchers[:1]
+	canary.Spec.Routing.Ports = append(canary.Spec.Routing.Ports, ringsv1alpha1.RingPort{Name: "metrics", Port: 9090})
+	errs = router.Validate(canary)
+	require.Len(t, errs, 1)
+	require.Equal(t, "spec.routing.ports", errs[0].Field)
+	canary.Spec.Routing.Ports = ports
+
+	// The weighted Ingress of a split doesn't take over the Ingress of a ring named like it
+	controller := true
+	weightedName := prodName + "-weighted"
+	other := &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      weightedName,
+			Namespace: namespace,
+			OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "rings.microsoft.com/v1alpha1", Kind: "Ring", Name: weightedName, UID: "weighted-uid", Controller: &controller},
+			},
+			Annotations: map[string]string{"nginx.ingress.kubernetes.io/canary-by-header-value": "weighted"},
+		},
+	}
+	err = cl.Create(context.TODO(), other)
+	require.NoError(t, err)
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), newRequest(prodName).NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Routing.Split = []ringsv1alpha1.RingBranchWeight{{Branch: "prod", Weight: 90}, {Branch: "next", Weight: 10}}
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(newRequest(prodName))
+	require.True(t, errors.IsConflict(err))
+
+	ing = &networkingv1.Ingress{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: weightedName, Namespace: namespace}, ing)
+	require.NoError(t, err)
+	require.Equal(t, "weighted", ing.Annotations["nginx.ingress.kubernetes.io/canary-by-header-value"])
+	require.Empty(t, ing.Annotations["nginx.ingress.kubernetes.io/canary-weight"])
+}
+
+func TestReconcileSMI(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	router, err := ring.NewRouter("smi")
+	require.NoError(t, err)
+
+	namespace := "default"
+	prodSelector := map[string]string{"service": "query", "version": "v1", "branch": "prod"}
+	canarySelector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	prodName, canaryName := "query-v1-prod", "query-v1-canary"
+
+	canary := createRing(canaryName, namespace, "canary", true, canarySelector)
+	canary.UID = types.UID("canary-uid")
+	prod := createRing(prodName, namespace, "*", true, prodSelector)
+	prod.UID = types.UID("prod-uid")
+	prod.Spec.Routing.Split = []ringsv1alpha1.RingBranchWeight{{Branch: "prod", Weight: 90}, {Branch: "next", Weight: 10}}
+
+	// Add Known CustomResourceDefinitions to the cluster scheme
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+	s.AddKnownTypes(split.SchemeGroupVersion, &split.TrafficSplit{}, &split.TrafficSplitList{})
+	s.AddKnownTypes(specs.SchemeGroupVersion, &specs.HTTPRouteGroup{}, &specs.HTTPRouteGroupList{})
+	cl := fake.NewFakeClient(canary)
+
+	// Create a request for reconciliation
+	r := &ring.ReconcileRing{Client: cl, Scheme: s, Router: router}
+	canaryReq := reconcile.Request{NamespacedName: types.NamespacedName{Name: canaryName, Namespace: namespace}}
+
+	// The canary waits for the production ring providing its root Service
+	_, err = r.Reconcile(canaryReq)
+	require.NoError(t, err)
+	found := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, found)
+	require.NoError(t, err)
+	for _, c := range found.Status.Conditions {
+		if c.Type == ringsv1alpha1.RingRoutingConfigured || c.Type == ringsv1alpha1.RingReady {
+			require.Equal(t, corev1.ConditionFalse, c.Status)
+			require.Equal(t, "WaitingForProductionRing", c.Reason)
+		}
+		if c.Type == ringsv1alpha1.RingRoutingConfigured {
+			require.Contains(t, c.Message, "no production ring of service query version v1")
+		}
+	}
+
+	// The TrafficSplits split the Service of the production ring
+
+	err = cl.Create(context.TODO(), prod)
+	require.NoError(t, err)
+	_, err = r.Reconcile(canaryReq)
+	require.NoError(t, err)
+
+	ts := &split.TrafficSplit{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, ts)
+	require.NoError(t, err)
+	require.Equal(t, prodName, ts.Spec.Service)
+	require.Equal(t, []split.TrafficSplitBackend{{Service: canaryName, Weight: 100}}, ts.Spec.Backends)
+	require.Len(t, ts.Spec.Matches, 1)
+	require.Equal(t, "HTTPRouteGroup", ts.Spec.Matches[0].Kind)
+	require.Equal(t, canaryName, ts.Spec.Matches[0].Name)
+
+	rg := &specs.HTTPRouteGroup{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, rg)
+	require.NoError(t, err)
+	require.Len(t, rg.Spec.Matches, 1)
+	require.Equal(t, map[string]string{"group": "^canary$"}, rg.Spec.Matches[0].Headers)
+
+	// The production ring weighs the root Service across its branches
+	ts = &split.TrafficSplit{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: prodName, Namespace: namespace}, ts)
+	require.NoError(t, err)
+	require.Equal(t, prodName, ts.Spec.Service)
+	require.Empty(t, ts.Spec.Matches)
+	require.Equal(t, []split.TrafficSplitBackend{{Service: prodName, Weight: 90}, {Service: prodName + "-next", Weight: 10}}, ts.Spec.Backends)
+
+	instance := &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, instance)
+	require.NoError(t, err)
+	require.Equal(t, canaryName, instance.Status.IngressRouteName)
+
+	// A ring on standby leaves the mesh
+	instance.Spec.Deploy = false
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(canaryReq)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, &split.TrafficSplit{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, &specs.HTTPRouteGroup{})
+	require.True(t, errors.IsNotFound(err))
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: prodName, Namespace: namespace}, &split.TrafficSplit{})
+	require.NoError(t, err)
+
+	// The TrafficSplit and HTTPRouteGroup written by the user for the name of a ring are not taken over
+	userSplit := &split.TrafficSplit{
+		ObjectMeta: metav1.ObjectMeta{Name: canaryName, Namespace: namespace},
+		Spec:       split.TrafficSplitSpec{Service: "query", Backends: []split.TrafficSplitBackend{{Service: "query-user", Weight: 100}}},
+	}
+	err = cl.Create(context.TODO(), userSplit)
+	require.NoError(t, err)
+	userGroup := &specs.HTTPRouteGroup{
+		ObjectMeta: metav1.ObjectMeta{Name: canaryName, Namespace: namespace},
+		Spec:       specs.HTTPRouteGroupSpec{Matches: []specs.HTTPMatch{{Name: "user", Headers: map[string]string{"user": "^yes$"}}}},
+	}
+	err = cl.Create(context.TODO(), userGroup)
+	require.NoError(t, err)
+
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Deploy = true
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(canaryReq)
+	require.True(t, errors.IsConflict(err))
+
+	ts = &split.TrafficSplit{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, ts)
+	require.NoError(t, err)
+	require.Equal(t, userSplit.Spec, ts.Spec)
+	require.Empty(t, ts.OwnerReferences)
+	rg = &specs.HTTPRouteGroup{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, rg)
+	require.NoError(t, err)
+	require.Equal(t, userGroup.Spec, rg.Spec)
+	require.Empty(t, rg.OwnerReferences)
+
+	// Withdrawing the ring leaves them in place
+	instance = &ringsv1alpha1.Ring{}
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, instance)
+	require.NoError(t, err)
+	instance.Spec.Deploy = false
+	err = cl.Update(context.TODO(), instance)
+	require.NoError(t, err)
+	_, err = r.Reconcile(canaryReq)
+	require.NoError(t, err)
+
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, &split.TrafficSplit{})
+	require.NoError(t, err)
+	err = cl.Get(context.TODO(), canaryReq.NamespacedName, &specs.HTTPRouteGroup{})
+	require.NoError(t, err)
+}
+
+func TestTraefikGroupMigration(t *testing.T) {
+	logf.SetLogger(logf.ZapLogger(true))
+
+	newDiscovery := func(groupVersions ...string) *fakediscovery.FakeDiscovery {
+		resources := []*metav1.APIResourceList{}
+		for _, gv := range groupVersions {
+			resources = append(resources, &metav1.APIResourceList{
+				GroupVersion: gv,
+				APIResources: []metav1.APIResource{{Name: "ingressroutes", Kind: "IngressRoute", Namespaced: true}},
+			})
+		}
+		return &fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: resources}}
+	}
+
+	// The rings are routed through the legacy group when the cluster only serves it
+	router, err := ring.NewRouter("traefik")
+	require.NoError(t, err)
+	err = router.(ring.APIDetector).DetectAPIs(newDiscovery("traefik.containo.us/v1alpha1"))
+	require.NoError(t, err)
+
+	s := runtime.NewScheme()
+	err = router.AddToScheme(s)
+	require.NoError(t, err)
+	gvks, _, err := s.ObjectKinds(&traefik.IngressRoute{})
+	require.NoError(t, err)
+	require.Equal(t, traefik.LegacyGroupName, gvks[0].Group)
+
+	// The objects of the rings move to traefik.io when the cluster serves both groups
+	router, err = ring.NewRouter("traefik")
+	require.NoError(t, err)
+	err = router.(ring.APIDetector).DetectAPIs(newDiscovery("traefik.containo.us/v1alpha1", "traefik.io/v1alpha1"))
+	require.NoError(t, err)
+
+	s = runtime.NewScheme()
+	err = router.AddToScheme(s)
+	require.NoError(t, err)
+	for _, kind := range traefik.Kinds {
+		s.AddKnownTypeWithName(traefik.LegacySchemeGroupVersion.WithKind(kind), &unstructured.Unstructured{})
+		s.AddKnownTypeWithName(traefik.LegacySchemeGroupVersion.WithKind(kind+"List"), &unstructured.UnstructuredList{})
+	}
+
+	newLegacy := func(kind, name string, owner *metav1.OwnerReference) *unstructured.Unstructured {
+		obj := &unstructured.Unstructured{Object: map[string]interface{}{
+			"spec": map[string]interface{}{"entryPoints": []interface{}{"web"}},
+		}}
+		obj.SetGroupVersionKind(traefik.LegacySchemeGroupVersion.WithKind(kind))
+		obj.SetName(name)
+		obj.SetNamespace("default")
+		obj.SetResourceVersion("42")
+		if owner != nil {
+			obj.SetOwnerReferences([]metav1.OwnerReference{*owner})
+		}
+		return obj
+	}
+	controller := true
+	owner := &metav1.OwnerReference{APIVersion: "rings.microsoft.com/v1alpha1", Kind: "Ring", Name: "query-v1-canary", UID: "uid", Controller: &controller}
+	elsewhere := newLegacy("IngressRoute", "query-v1-master", owner)
+	elsewhere.SetNamespace("other")
+	objs := []runtime.Object{
+		newLegacy("IngressRoute", "query-v1-canary", owner),
+		newLegacy("IngressRoute", "unmanaged", nil),
+		elsewhere,
+	}
+	cl := fake.NewFakeClientWithScheme(s, objs...)
+
+	err = router.(ring.LegacyMigrator).MigrateLegacy(&unstructuredListClient{Client: cl, objs: objs}, "default")
+	require.NoError(t, err)
+
+	route := &traefik.IngressRoute{}
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "query-v1-canary", Namespace: "default"}, route)
+	require.NoError(t, err)
+	require.Equal(t, []string{"web"}, route.Spec.EntryPoints)
+	require.True(t, metav1.IsControlledBy(route, &ringsv1alpha1.Ring{ObjectMeta: metav1.ObjectMeta{UID: "uid"}}))
+
+	legacy := &unstructured.Unstructured{}
+	legacy.SetGroupVersionKind(traefik.LegacySchemeGroupVersion.WithKind("IngressRoute"))
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "query-v1-canary", Namespace: "default"}, legacy)
+	require.True(t, errors.IsNotFound(err))
+
+	// The objects which are not controlled by a ring are left alone
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "unmanaged", Namespace: "default"}, legacy)
+	require.NoError(t, err)
+
+	// The objects outside of the watched namespace are left alone
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: "query-v1-master", Namespace: "other"}, legacy)
+	require.NoError(t, err)
+
+	// The rules are written in the v3 syntax when traefik.io serves the resources of Traefik v3
+	v3 := newDiscovery("traefik.io/v1alpha1")
+	v3.Resources[0].APIResources = append(v3.Resources[0].APIResources, metav1.APIResource{Name: "serverstransporttcps", Kind: "ServersTransportTCP", Namespaced: true})
+	router, err = ring.NewRouter("traefik")
+	require.NoError(t, err)
+	err = router.(ring.APIDetector).DetectAPIs(v3)
+	require.NoError(t, err)
+
+	selector := map[string]string{"service": "query", "version": "v1", "branch": "canary"}
+	instance := createRing("query-v1-canary", "default", "canary", true, selector)
+	instance.Spec.Routing.Hosts = []string{"api.example.com", "*.preview.example.com"}
+	routing, err := router.Route(instance)
+	require.NoError(t, err)
+	require.Equal(t, "(Host(`api.example.com`) || HostRegexp(`^[a-zA-Z0-9-]+\\.preview\\.example\\.com$`)) && "+
+		"PathPrefix(`/query/v1`) && Header(`group`, `canary`)", routing.Match)
+
+	// Traefik v3 no longer serves the ipWhiteList middleware
+	instance.Spec.Routing.Middlewares = []ringsv1alpha1.RingMiddleware{
+		{Name: "internal", Spec: &runtime.RawExtension{Raw: []byte(`{"ipWhiteList":{"sourceRange":["10.0.0.0/8"]}}`)}},
+	}
+	errs := router.Validate(instance)
+	require.Len(t, errs, 1)
+	require.Equal(t, "spec.routing.middlewares[0].spec.ipWhiteList", errs[0].Field)
+}
+
+// unstructuredListClient lists the given unstructured objects, the fake client can't list unstructured objects
+type unstructuredListClient struct {
+	client.Client
+	objs []runtime.Object
+}
+
+func (c *unstructuredListClient) List(ctx context.Context, opts *client.ListOptions, list runtime.Object) error {
+	u, ok := list.(*unstructured.UnstructuredList)
+	if !ok {
+		return c.Client.List(ctx, opts, list)
+	}
+	kind := strings.TrimSuffix(u.GetKind(), "List")
+	for _, obj := range c.objs {
+		item := obj.(*unstructured.Unstructured)
+		if opts.Namespace != "" && item.GetNamespace() != opts.Namespace {
+			continue
+		}
+		if item.GroupVersionKind() == u.GroupVersionKind().GroupVersion().WithKind(kind) {
+			u.Items = append(u.Items, *item.DeepCopy())
+		}
+	}
+	return nil
 }
@@ -0,0 +1,50 @@
+package ring
+
+import (
+	"os"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+)
+
+const (
+	// defaultPortName names the port of the rings exposing a single unnamed port
+	defaultPortName = "default"
+
+	// branchLabel is the label of the ring naming its branch when the routing doesn't
+	branchLabel = "branch"
+)
+
+// DefaultRing fills in the parts of the ring spec which are left to their defaults
+// The mutating webhook stores them on the ring, and the controller applies them again on the rings
+// admitted without it so both agree on the routing
+func DefaultRing(cr *ringsv1alpha1.Ring) {
+	routing := &cr.Spec.Routing
+
+	for i := range routing.Ports {
+		port := &routing.Ports[i]
+		if port.Protocol == "" {
+			port.Protocol = corev1.ProtocolTCP
+		}
+		if port.TargetPort.Type == intstr.Int && port.TargetPort.IntVal == 0 {
+			port.TargetPort = intstr.FromInt(int(port.Port))
+		}
+	}
+	if len(routing.Ports) == 1 && routing.Ports[0].Name == "" {
+		routing.Ports[0].Name = defaultPortName
+	}
+
+	if routing.Branch == "" {
+		routing.Branch = cr.Labels[branchLabel]
+	}
+
+	// Rings which don't target any group or matcher are routed to the operator default group
+	if len(getRingGroups(routing)) == 0 && len(routing.Matchers) == 0 {
+		if group := strings.TrimSpace(os.Getenv("RING_DEFAULT_GROUP")); group != "" {
+			routing.Groups = []ringsv1alpha1.RingGroup{{Name: group}}
+		}
+	}
+}
@@ -0,0 +1,129 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// isRequiringReadyEndpoints reports whether the ring is only routed once its branches have ready endpoints
+func isRequiringReadyEndpoints(routing *ringsv1alpha1.RingRouting) bool {
+	if routing.RequireReadyEndpoints != nil {
+		return *routing.RequireReadyEndpoints
+	}
+	return strings.ToLower(os.Getenv("RING_REQUIRE_READY_ENDPOINTS")) == "true"
+}
+
+// checkEndpoints reports on the status whether the Services of every branch of the ring have ready endpoints
+// and returns whether the ring may be routed
+// The production ring stays routed without ready endpoints as there is no ring for its requests to fall back to
+func (r *ReconcileRing) checkEndpoints(cr *ringsv1alpha1.Ring, status *ringsv1alpha1.RingStatus) (bool, error) {
+	routing := &cr.Spec.Routing
+	if !isRequiringReadyEndpoints(routing) {
+		removeCondition(status, ringsv1alpha1.RingEndpointsReady)
+		return true, nil
+	}
+
+	unready := []string{}
+	for _, branch := range getRingBranches(routing) {
+		ready, err := r.hasReadyEndpoints(cr.Namespace, getSplitServiceName(cr, branch))
+		if err != nil {
+			return false, err
+		}
+		if !ready {
+			unready = append(unready, branch)
+		}
+	}
+
+	if len(unready) == 0 {
+		setCondition(status, ringsv1alpha1.RingEndpointsReady, corev1.ConditionTrue, "EndpointsReady", "")
+		return true, nil
+	}
+	message := fmt.Sprintf("no ready endpoints for branch %s", strings.Join(unready, ", "))
+	setCondition(status, ringsv1alpha1.RingEndpointsReady, corev1.ConditionFalse, "NoReadyEndpoints", message)
+	return isProductionRing(routing), nil
+}
+
+// hasReadyEndpoints returns whether the named Service has at least one ready address
+func (r *ReconcileRing) hasReadyEndpoints(namespace, name string) (bool, error) {
+	endpoints := &corev1.Endpoints{}
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: namespace}, endpoints)
+	if err != nil && apierrors.IsNotFound(err) {
+		return false, nil
+	} else if err != nil {
+		r.logger.Error(err, "Could not get Endpoints", "Name", name)
+		return false, err
+	}
+
+	for _, subset := range endpoints.Subsets {
+		if len(subset.Addresses) > 0 {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// mapDeploymentToRings returns the rings of the namespace of a Deployment with a branch selecting its pods
+func mapDeploymentToRings(c client.Client) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		deployment, ok := obj.Object.(*appsv1.Deployment)
+		if !ok {
+			return nil
+		}
+
+		rings := &ringsv1alpha1.RingList{}
+		if err := c.List(context.TODO(), client.InNamespace(deployment.Namespace), rings); err != nil {
+			log.Error(err, "Could not list Rings", "Namespace", deployment.Namespace)
+			return nil
+		}
+
+		requests := []reconcile.Request{}
+		for i := range rings.Items {
+			routing := &getDesiredRing(&rings.Items[i]).Spec.Routing
+			for _, branch := range getRingBranches(routing) {
+				selector := labels.SelectorFromSet(getServiceSelector(routing, branch))
+				if selector.Matches(labels.Set(deployment.Spec.Template.Labels)) {
+					requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{
+						Name:      rings.Items[i].Name,
+						Namespace: rings.Items[i].Namespace,
+					}})
+					break
+				}
+			}
+		}
+		return requests
+	}
+}
+
+// mapEndpointsToRing returns the ring controlling the Service of the Endpoints
+func mapEndpointsToRing(c client.Client) handler.ToRequestsFunc {
+	return func(obj handler.MapObject) []reconcile.Request {
+		svc := &corev1.Service{}
+		name := types.NamespacedName{Name: obj.Meta.GetName(), Namespace: obj.Meta.GetNamespace()}
+		if err := c.Get(context.TODO(), name, svc); err != nil {
+			if !apierrors.IsNotFound(err) {
+				log.Error(err, "Could not get Service of Endpoints", "Namespace", name.Namespace, "Name", name.Name)
+			}
+			return nil
+		}
+
+		owner := metav1.GetControllerOf(svc)
+		if owner == nil || owner.Kind != "Ring" || !strings.HasPrefix(owner.APIVersion, ringsv1alpha1.SchemeGroupVersion.Group+"/") {
+			return nil
+		}
+		return []reconcile.Request{{NamespacedName: types.NamespacedName{Name: owner.Name, Namespace: svc.Namespace}}}
+	}
+}
@@ -15,7 +15,7 @@ func (r *ReconcileRing) finalizeRing(cr *ringsv1alpha1.Ring) error {
 	// TODO - Add check if this is the last ring for that group
 	// Only the delete the group if no other rings are using it
 
-	//return r.deleteADGroup(cr)
+	//return r.deleteADGroup(cr.Spec.Routing.Group.Name)
 	return nil
 }
 
@@ -0,0 +1,316 @@
+package ring
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	traefik "github.com/microsoft/ring-operator/pkg/traefik/v1alpha1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+// ringBranchHeader tells which branch of the ring served a request
+const ringBranchHeader = "X-Ring-Branch"
+
+// ringMiddleware is a Middleware applied to the requests routed to the ring
+// It is owned by the ring unless it is a reference to an existing Middleware
+type ringMiddleware struct {
+	name      string
+	namespace string
+	ref       bool
+	spec      traefik.MiddlewareSpec
+}
+
+// getRingMiddlewares returns the Middlewares the ring needs, in the order they are applied
+func getRingMiddlewares(cr *ringsv1alpha1.Ring) ([]ringMiddleware, error) {
+	routing := &cr.Spec.Routing
+	path := getRingPath(routing)
+	middlewares := []ringMiddleware{}
+
+	// Stamping first lets the responses refused by the other middlewares be told apart too
+	if isStampingHeaders(routing) {
+		middlewares = append(middlewares, ringMiddleware{
+			name: fmt.Sprintf(stampMiddlewareName, cr.Name),
+			spec: traefik.MiddlewareSpec{Headers: getStampHeaders(cr)},
+		})
+	}
+
+	// Requests over the limit are refused before anything else is done with them
+	if routing.RateLimit != nil {
+		middlewares = append(middlewares, ringMiddleware{
+			name: fmt.Sprintf(ratelimitMiddlewareName, cr.Name),
+			spec: traefik.MiddlewareSpec{RateLimit: getRateLimit(routing.RateLimit)},
+		})
+	}
+
+	for _, m := range routing.Middlewares {
+		if m.Spec == nil {
+			middlewares = append(middlewares, ringMiddleware{
+				name:      m.Name,
+				namespace: m.Namespace,
+				ref:       true,
+			})
+			continue
+		}
+		spec, err := decodeMiddlewareSpec(m.Spec)
+		if err != nil {
+			return nil, fmt.Errorf("middleware %q has an invalid spec: %v", m.Name, err)
+		}
+		middlewares = append(middlewares, ringMiddleware{
+			name: fmt.Sprintf(userMiddlewareName, cr.Name, m.Name),
+			spec: *spec,
+		})
+	}
+
+	switch getPathRewrite(routing) {
+	case ringsv1alpha1.RewriteStrip:
+		// There is nothing to strip from rings routed on every path
+		if path != "" {
+			middlewares = append(middlewares, ringMiddleware{
+				name: fmt.Sprintf(stripPrefixMiddlewareName, cr.Name),
+				spec: traefik.MiddlewareSpec{
+					StripPrefix: &traefik.StripPrefix{Prefixes: []string{path}},
+				},
+			})
+		}
+	case ringsv1alpha1.RewriteReplace:
+		regex := routing.Path.Regex
+		if regex == "" {
+			regex = "^" + regexp.QuoteMeta(path) + "(.*)"
+		}
+		middlewares = append(middlewares, ringMiddleware{
+			name: fmt.Sprintf(replacePathMiddlewareName, cr.Name),
+			spec: traefik.MiddlewareSpec{
+				ReplacePathRegex: &traefik.ReplacePathRegex{
+					Regex:       regex,
+					Replacement: routing.Path.Replacement,
+				},
+			},
+		})
+	}
+	return middlewares, nil
+}
+
+// decodeMiddlewareSpec reads the inline spec of a middleware of the ring as the spec of a Traefik Middleware
+// The fields Traefik Middlewares don't have are refused rather than dropped, they would go unnoticed otherwise
+func decodeMiddlewareSpec(raw *runtime.RawExtension) (*traefik.MiddlewareSpec, error) {
+	spec := &traefik.MiddlewareSpec{}
+	decoder := json.NewDecoder(bytes.NewReader(raw.Raw))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+// getMiddlewareRefs returns the references of the IngressRoute to the Middlewares of the ring
+func getMiddlewareRefs(cr *ringsv1alpha1.Ring, middlewares []ringMiddleware) []traefik.MiddlewareRef {
+	refs := []traefik.MiddlewareRef{}
+	for _, m := range middlewares {
+		namespace := m.namespace
+		if namespace == "" {
+			namespace = cr.Namespace
+		}
+		refs = append(refs, traefik.MiddlewareRef{
+			Name:      m.name,
+			Namespace: namespace,
+		})
+	}
+	return refs
+}
+
+// isStampingHeaders reports whether the requests of the ring and their responses are stamped with the ring headers
+func isStampingHeaders(routing *ringsv1alpha1.RingRouting) bool {
+	if routing.StampHeaders != nil {
+		return *routing.StampHeaders
+	}
+	return strings.ToLower(os.Getenv("RING_STAMP_HEADERS")) == "true"
+}
+
+// getStampHeaders returns the Headers configuration telling which ring served a request
+// The branch is left out of rings splitting their traffic, the branch serving the request is only
+// picked after the middlewares ran and Traefik has no middleware per weighted service
+func getStampHeaders(cr *ringsv1alpha1.Ring) *traefik.Headers {
+	headers := getStampHeaderValues(cr)
+	return &traefik.Headers{
+		CustomRequestHeaders:  headers,
+		CustomResponseHeaders: headers,
+	}
+}
+
+// getStampHeaderValues returns the headers telling which ring served a request by name
+// The branch of a ring splitting its traffic is left to the routers stamping every weighted branch
+func getStampHeaderValues(cr *ringsv1alpha1.Ring) map[string]string {
+	routing := cr.Spec.Routing
+	headers := map[string]string{
+		"X-Ring-Name":    cr.Name,
+		"X-Ring-Version": routing.Version,
+	}
+	if len(routing.Split) == 0 {
+		headers[ringBranchHeader] = routing.Branch
+	}
+	return headers
+}
+
+// getRateLimit returns the Traefik RateLimit configuration of the ring rate limit
+func getRateLimit(limit *ringsv1alpha1.RingRateLimit) *traefik.RateLimit {
+	rateLimit := &traefik.RateLimit{
+		Average: limit.Average,
+		Burst:   limit.Burst,
+	}
+	if limit.Period != nil {
+		rateLimit.Period = limit.Period.Duration.String()
+	}
+
+	if src := limit.Source; src != nil {
+		criterion := &traefik.SourceCriterion{
+			RequestHeaderName: src.RequestHeaderName,
+			RequestHost:       src.RequestHost,
+		}
+		if src.IPDepth > 0 || len(src.ExcludedIPs) > 0 {
+			criterion.IPStrategy = &traefik.IPStrategy{
+				Depth:       src.IPDepth,
+				ExcludedIPs: src.ExcludedIPs,
+			}
+		}
+		rateLimit.SourceCriterion = criterion
+	}
+	return rateLimit
+}
+
+// validateRateLimit checks the rate limit can be enforced by Traefik
+func validateRateLimit(limit *ringsv1alpha1.RingRateLimit) error {
+	if limit == nil {
+		return nil
+	}
+
+	if limit.Average <= 0 {
+		return errors.New("rate limit average must be greater than 0")
+	}
+	if limit.Burst != nil && *limit.Burst < 0 {
+		return errors.New("rate limit burst must not be negative")
+	}
+	if limit.Period != nil && limit.Period.Duration <= 0 {
+		return errors.New("rate limit period must be greater than 0")
+	}
+
+	if src := limit.Source; src != nil {
+		criteria := 0
+		if src.IPDepth != 0 || len(src.ExcludedIPs) > 0 {
+			criteria++
+		}
+		if src.RequestHeaderName != "" {
+			criteria++
+		}
+		if src.RequestHost {
+			criteria++
+		}
+		if criteria > 1 {
+			return errors.New("rate limit source must group requests by only one of client address, header or host")
+		}
+		if src.IPDepth < 0 {
+			return errors.New("rate limit source ipDepth must not be negative")
+		}
+	}
+	return nil
+}
+
+// validateMiddlewares checks the middlewares listed on the ring can be referenced or created
+func validateMiddlewares(cr *ringsv1alpha1.Ring) error {
+	reserved := map[string]bool{
+		fmt.Sprintf(stampMiddlewareName, cr.Name):       true,
+		fmt.Sprintf(ratelimitMiddlewareName, cr.Name):   true,
+		fmt.Sprintf(stripPrefixMiddlewareName, cr.Name): true,
+		fmt.Sprintf(replacePathMiddlewareName, cr.Name): true,
+	}
+	seen := map[string]bool{}
+
+	for i, m := range cr.Spec.Routing.Middlewares {
+		if m.Name == "" {
+			return fmt.Errorf("middleware %d must have a name", i)
+		}
+		if m.Spec == nil {
+			continue
+		}
+
+		if m.Namespace != "" {
+			return fmt.Errorf("middleware %q has a spec and can only be created in the namespace of the ring", m.Name)
+		}
+		name := fmt.Sprintf(userMiddlewareName, cr.Name, m.Name)
+		if errs := validation.IsDNS1123Subdomain(name); len(errs) > 0 {
+			return fmt.Errorf("middleware name %q is invalid: %s", name, strings.Join(errs, ", "))
+		}
+		if reserved[name] {
+			return fmt.Errorf("middleware name %q is used by the operator", m.Name)
+		}
+		if seen[name] {
+			return fmt.Errorf("middleware %q is listed more than once", m.Name)
+		}
+		seen[name] = true
+
+		spec, err := decodeMiddlewareSpec(m.Spec)
+		if err != nil {
+			return fmt.Errorf("middleware %q has an invalid spec: %v", m.Name, err)
+		}
+		if n := countMiddlewares(spec); n != 1 {
+			return fmt.Errorf("middleware %q must set exactly one middleware, it sets %d", m.Name, n)
+		}
+	}
+	return nil
+}
+
+// countMiddlewares returns how many middlewares the spec sets, Traefik expects a single one
+func countMiddlewares(spec *traefik.MiddlewareSpec) int {
+	set := []bool{
+		spec.StripPrefix != nil,
+		spec.ReplacePathRegex != nil,
+		spec.RateLimit != nil,
+		spec.Headers != nil,
+		spec.Compress != nil,
+		spec.Retry != nil,
+		spec.BasicAuth != nil,
+		spec.ForwardAuth != nil,
+		spec.IPWhiteList != nil,
+		spec.RedirectScheme != nil,
+	}
+
+	n := 0
+	for _, s := range set {
+		if s {
+			n++
+		}
+	}
+	return n
+}
+
+// validatePath checks the path of the ring can be routed and rewritten
+func validatePath(routing *ringsv1alpha1.RingRouting) error {
+	path := routing.Path
+	if path == nil {
+		return nil
+	}
+
+	if !path.Disabled && path.Template != "" && path.Template[0] != '/' {
+		return fmt.Errorf("path template %q must start with /", path.Template)
+	}
+
+	if getPathRewrite(routing) != ringsv1alpha1.RewriteReplace {
+		return nil
+	}
+	if path.Disabled && path.Regex == "" {
+		return errors.New("path rewrite Replace requires a regex when path routing is disabled")
+	}
+	if path.Regex != "" {
+		if _, err := regexp.Compile(path.Regex); err != nil {
+			return fmt.Errorf("path regex %q is invalid: %v", path.Regex, err)
+		}
+	}
+	return nil
+}
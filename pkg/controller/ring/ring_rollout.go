@@ -0,0 +1,221 @@
+package ring
+
+import (
+	"encoding/json"
+	"fmt"
+	"hash/fnv"
+	"time"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// rolloutPausedAnnotation holds the rollout on its current step while set to "true"
+	// Removing it resumes the rollout and restarts the bake of the current step
+	rolloutPausedAnnotation = "rings.microsoft.com/rollout-paused"
+	// rolloutAbortAnnotation routes the ring as if it had no rollout while set to "true"
+	// Removing it starts the rollout over from the first step
+	rolloutAbortAnnotation = "rings.microsoft.com/rollout-abort"
+)
+
+// progressRollout moves the rollout of the ring to the step it should be on and records it in the status
+// It returns how long to wait before the current step has baked, or 0 when there is nothing to wait for
+func (r *ReconcileRing) progressRollout(cr *ringsv1alpha1.Ring) (time.Duration, error) {
+	rollout := cr.Spec.Rollout
+	if rollout == nil || rollout.Analysis == nil {
+		removeCondition(&cr.Status, ringsv1alpha1.RingAnalysisReady)
+	}
+	if rollout == nil || len(rollout.Steps) == 0 {
+		cr.Status.Rollout = nil
+		return 0, nil
+	}
+
+	if err := validateRollout(rollout); err != nil {
+		return 0, err
+	}
+
+	now := metav1.Now()
+	hash, err := getRolloutHash(rollout)
+	if err != nil {
+		return 0, err
+	}
+	st := cr.Status.Rollout
+	switch {
+	case st == nil:
+		r.logger.Info("Starting rollout")
+		st = newRolloutStatus(now, hash)
+		cr.Status.Rollout = st
+	case st.SpecHash == "":
+		// The rollouts recorded before the hash existed go on with the steps they were started with
+		st.SpecHash = hash
+	case st.SpecHash != hash:
+		// A new branch or new steps are rolled out from the first step, even after the last rollout ended
+		r.logger.Info("Rollout changed, restarting rollout", "Step", st.Step)
+		*st = *newRolloutStatus(now, hash)
+	}
+
+	// The steps may have been shortened since the status was recorded
+	if st.Step >= len(rollout.Steps) {
+		st.Step = len(rollout.Steps) - 1
+	}
+
+	annotations := cr.GetAnnotations()
+	switch {
+	case annotations[rolloutAbortAnnotation] == "true":
+		if st.Phase != ringsv1alpha1.RolloutAborted {
+			r.logger.Info("Aborting rollout", "Step", st.Step)
+			st.Phase = ringsv1alpha1.RolloutAborted
+			st.CompletionTime = &now
+		}
+		return 0, nil
+	case st.Phase == ringsv1alpha1.RolloutAborted:
+		r.logger.Info("Abort lifted, restarting rollout")
+		*st = *newRolloutStatus(now, hash)
+	case annotations[rolloutPausedAnnotation] == "true":
+		if st.Phase == ringsv1alpha1.RolloutProgressing {
+			r.logger.Info("Pausing rollout", "Step", st.Step)
+			st.Phase = ringsv1alpha1.RolloutPaused
+		}
+		return 0, nil
+	case st.Phase == ringsv1alpha1.RolloutPaused:
+		r.logger.Info("Resuming rollout", "Step", st.Step)
+		st.Phase = ringsv1alpha1.RolloutProgressing
+		st.StepStartTime = &now
+	}
+
+	if st.Phase == ringsv1alpha1.RolloutCompleted || st.Phase == ringsv1alpha1.RolloutRolledBack {
+		return 0, nil
+	}
+
+	// The analysis runs once per interval so a breach rolls the ring back while the step bakes, the passes in
+	// between keep the result of the last analysis
+	hold := false
+	interval := time.Duration(0)
+	if rollout.Analysis != nil {
+		interval = getAnalysisInterval(rollout.Analysis)
+		var result ringsv1alpha1.RingAnalysisResult
+		if last := st.Analysis; last != nil && now.Sub(last.Time.Time) < interval {
+			result = last.Result
+			interval -= now.Sub(last.Time.Time)
+		} else {
+			result = r.analyzeRollout(cr)
+		}
+
+		switch result {
+		case ringsv1alpha1.AnalysisFailed:
+			r.logger.Info("Rolling back rollout", "Step", st.Step, "Reason", st.Analysis.Message)
+			st.Phase = ringsv1alpha1.RolloutRolledBack
+			st.CompletionTime = &now
+			return 0, nil
+		case ringsv1alpha1.AnalysisInconclusive:
+			hold = true
+		}
+	}
+
+	for {
+		bake := rollout.Steps[st.Step].Bake.Duration
+		if elapsed := now.Sub(st.StepStartTime.Time); elapsed < bake {
+			return minRequeue(bake-elapsed, interval), nil
+		}
+
+		if hold {
+			r.logger.Info("Holding rollout step until the analysis is conclusive", "Step", st.Step)
+			return interval, nil
+		}
+
+		if st.Step == len(rollout.Steps)-1 {
+			r.logger.Info("Rollout completed")
+			st.Phase = ringsv1alpha1.RolloutCompleted
+			st.CompletionTime = &now
+			return 0, nil
+		}
+
+		st.Step++
+		st.StepStartTime = &now
+		r.logger.Info("Rollout moved to the next step", "Step", st.Step)
+	}
+}
+
+// minRequeue returns the shortest of the non zero durations
+func minRequeue(a, b time.Duration) time.Duration {
+	if a == 0 || (b != 0 && b < a) {
+		return b
+	}
+	return a
+}
+
+// newRolloutStatus returns the status of a rollout of the given hash starting on its first step
+func newRolloutStatus(now metav1.Time, hash string) *ringsv1alpha1.RingRolloutStatus {
+	return &ringsv1alpha1.RingRolloutStatus{
+		Phase:         ringsv1alpha1.RolloutProgressing,
+		Step:          0,
+		SpecHash:      hash,
+		StartTime:     &now,
+		StepStartTime: &now,
+	}
+}
+
+// getRolloutHash returns the hash of the branch and steps of the rollout, the analysis is left out as changing
+// it doesn't change what is rolled out
+func getRolloutHash(rollout *ringsv1alpha1.RingRollout) (string, error) {
+	raw, err := json.Marshal(struct {
+		Branch string                          `json:"branch"`
+		Steps  []ringsv1alpha1.RingRolloutStep `json:"steps"`
+	}{rollout.Branch, rollout.Steps})
+	if err != nil {
+		return "", err
+	}
+	h := fnv.New64a()
+	h.Write(raw)
+	return fmt.Sprintf("%x", h.Sum64()), nil
+}
+
+// validateRollout checks that every step of the rollout can be applied to the ring
+func validateRollout(rollout *ringsv1alpha1.RingRollout) error {
+	for i, step := range rollout.Steps {
+		if (step.Weight == nil) == (step.Group == "") {
+			return fmt.Errorf("rollout step %d must set exactly one of weight or group", i)
+		}
+		if step.Weight != nil {
+			if *step.Weight < 0 || *step.Weight > 100 {
+				return fmt.Errorf("rollout step %d weight must be between 0 and 100", i)
+			}
+			if rollout.Branch == "" {
+				return fmt.Errorf("rollout step %d sets a weight but the rollout has no branch", i)
+			}
+		}
+		if step.Bake.Duration < 0 {
+			return fmt.Errorf("rollout step %d bake must not be negative", i)
+		}
+	}
+	return nil
+}
+
+// getDesiredRing returns a copy of the ring with its defaults and the routing of its current rollout step applied
+// The children of the ring are built from it, the ring itself is left untouched
+func getDesiredRing(cr *ringsv1alpha1.Ring) *ringsv1alpha1.Ring {
+	desired := cr.DeepCopy()
+	DefaultRing(desired)
+
+	rollout, st := cr.Spec.Rollout, cr.Status.Rollout
+	if rollout == nil || st == nil || st.Step >= len(rollout.Steps) ||
+		st.Phase == ringsv1alpha1.RolloutAborted || st.Phase == ringsv1alpha1.RolloutRolledBack {
+		return desired
+	}
+
+	step := rollout.Steps[st.Step]
+	routing := &desired.Spec.Routing
+	if step.Weight != nil {
+		routing.Split = []ringsv1alpha1.RingBranchWeight{
+			{Branch: routing.Branch, Weight: 100 - *step.Weight},
+			{Branch: rollout.Branch, Weight: *step.Weight},
+		}
+	}
+	if step.Group != "" {
+		routing.Group = ringsv1alpha1.RingGroup{Name: step.Group}
+		routing.Groups = nil
+	}
+	return desired
+}
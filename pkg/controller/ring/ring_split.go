@@ -0,0 +1,117 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	traefik "github.com/microsoft/ring-operator/pkg/traefik/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// reconcileSplit ensures a Service exists for every branch of the ring split, the router spreads the ring
+// traffic across them. Services which are no longer needed by the split are removed
+func (r *ReconcileRing) reconcileSplit(cr *ringsv1alpha1.Ring) error {
+	split := cr.Spec.Routing.Split
+	if err := validateSplit(split); err != nil {
+		return err
+	}
+
+	services := map[string]bool{cr.Name: true}
+	for _, b := range split {
+		name := getSplitServiceName(cr, b.Branch)
+		services[name] = true
+
+		// The ring Service already targets the ring branch
+		if name == cr.Name {
+			continue
+		}
+
+		r.debug.Info("Ensure split Service exists", "Branch", b.Branch)
+		if _, err := r.createOrUpdateService(cr, name, b.Branch); err != nil {
+			r.logger.Error(err, "Could not create or update split Service", "Branch", b.Branch)
+			return err
+		}
+	}
+
+	r.debug.Info("Removing Services no longer part of the split")
+	if err := r.pruneSplitServices(cr, services); err != nil {
+		r.logger.Error(err, "Could not remove split Services")
+		return err
+	}
+	return nil
+}
+
+// validateSplit checks the split can be expressed as a weighted round robin
+func validateSplit(split []ringsv1alpha1.RingBranchWeight) error {
+	total := 0
+	branches := map[string]bool{}
+	for _, b := range split {
+		if b.Weight < 0 {
+			return fmt.Errorf("split weight of branch %q must not be negative", b.Branch)
+		}
+		if branches[b.Branch] {
+			return fmt.Errorf("split lists branch %q more than once", b.Branch)
+		}
+		branches[b.Branch] = true
+		total += b.Weight
+	}
+
+	if len(split) > 0 && total == 0 {
+		return fmt.Errorf("split weights must add up to more than 0")
+	}
+	return nil
+}
+
+// getSplitServiceName returns the name of the Service targeting the given branch of the ring
+// The ring branch is served by the ring Service itself
+func getSplitServiceName(cr *ringsv1alpha1.Ring, branch string) string {
+	if branch == cr.Spec.Routing.Branch {
+		return cr.Name
+	}
+	return fmt.Sprintf(splitServiceName, cr.Name, branch)
+}
+
+// pruneSplitServices deletes the Services controlled by the ring which are not in the keep set
+func (r *ReconcileRing) pruneSplitServices(cr *ringsv1alpha1.Ring, keep map[string]bool) error {
+	svcList := &corev1.ServiceList{}
+	if err := r.Client.List(context.TODO(), client.InNamespace(cr.Namespace), svcList); err != nil {
+		r.logger.Error(err, "Could not list Services")
+		return err
+	}
+
+	for i := range svcList.Items {
+		svc := &svcList.Items[i]
+		if keep[svc.Name] || !metav1.IsControlledBy(svc, cr) {
+			continue
+		}
+
+		r.logger.Info("Deleting split Service", "Service.Namespace", svc.Namespace, "Service.Name", svc.Name)
+		if err := r.Client.Delete(context.TODO(), svc); err != nil && !errors.IsNotFound(err) {
+			r.logger.Error(err, "Could not delete split Service")
+			return err
+		}
+	}
+	return nil
+}
+
+// getWeightedServices returns the Services of the split with their weight
+// Every port of the ring is exposed for every branch so the ratio between branches is kept
+func getWeightedServices(cr *ringsv1alpha1.Ring) []traefik.Service {
+	routing := cr.Spec.Routing
+	services := []traefik.Service{}
+	for _, b := range routing.Split {
+		weight := b.Weight
+		for _, port := range routing.Ports {
+			services = append(services, traefik.Service{
+				Name:   getSplitServiceName(cr, b.Branch),
+				Port:   port.Port,
+				Weight: &weight,
+			})
+		}
+	}
+	return services
+}
@@ -0,0 +1,103 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// getRingState returns the state the ring is reconciled to
+// A ring which is not deployed is on standby unless it is disabled
+func getRingState(cr *ringsv1alpha1.Ring) ringsv1alpha1.RingState {
+	state := cr.Spec.State
+	if state == "" {
+		state = ringsv1alpha1.RingActive
+	}
+	if !cr.Spec.Deploy && state == ringsv1alpha1.RingActive {
+		return ringsv1alpha1.RingStandby
+	}
+	return state
+}
+
+// isRingActive returns whether the ring is routed
+func isRingActive(cr *ringsv1alpha1.Ring) bool {
+	return getRingState(cr) == ringsv1alpha1.RingActive
+}
+
+// withdrawRoute removes the children routing requests to the ring: the routing objects of its router, its
+// routes in the routing objects shared with other rings and the Services of the other branches of its split
+// The Service of the ring branch is kept
+func (r *ReconcileRing) withdrawRoute(cr *ringsv1alpha1.Ring, router Router, status *ringsv1alpha1.RingStatus) error {
+	r.debug.Info("Removing routing objects", "Router", router.Name())
+	if err := r.pruneOwned(cr, router, nil); err != nil {
+		return err
+	}
+	if _, err := r.reconcileSharedRouting(cr, router, false); err != nil {
+		return err
+	}
+	status.IngressRouteName = ""
+	status.Match = ""
+	status.MiddlewareNames = nil
+	removeCondition(status, ringsv1alpha1.RingEndpointsReady)
+	removeCondition(status, ringsv1alpha1.RingRouteAccepted)
+
+	r.debug.Info("Removing weighted split")
+	return r.pruneSplitServices(cr, map[string]bool{cr.Name: true})
+}
+
+// disableRing removes every child of the ring
+func (r *ReconcileRing) disableRing(cr *ringsv1alpha1.Ring, router Router, status *ringsv1alpha1.RingStatus) error {
+	if err := r.withdrawRoute(cr, router, status); err != nil {
+		return err
+	}
+
+	r.debug.Info("Removing Service")
+	if err := r.deleteOwned(cr, &corev1.Service{}, cr.Name); err != nil {
+		return err
+	}
+	status.ServiceName = ""
+
+	if isCertManagerEnabled() {
+		r.debug.Info("Removing Certificate")
+		if err := r.deleteCertificate(cr); err != nil {
+			return err
+		}
+	}
+	status.CertificateName = ""
+	removeCondition(status, ringsv1alpha1.RingCertificateReady)
+	removeCondition(status, ringsv1alpha1.RingWorkloadVerified)
+	return nil
+}
+
+// deleteOwned deletes the named object of the ring namespace if the ring controls it
+func (r *ReconcileRing) deleteOwned(cr *ringsv1alpha1.Ring, obj runtime.Object, name string) error {
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: name, Namespace: cr.Namespace}, obj)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		r.logger.Error(err, "Could not get existing child", "Name", name)
+		return err
+	}
+
+	meta, ok := obj.(metav1.Object)
+	if !ok {
+		return fmt.Errorf("%T is not a Kubernetes object", obj)
+	}
+	if !metav1.IsControlledBy(meta, cr) {
+		return nil
+	}
+
+	r.logger.Info("Deleting child", "Kind", fmt.Sprintf("%T", obj), "Namespace", cr.Namespace, "Name", name)
+	if err := r.Client.Delete(context.TODO(), obj); err != nil && !apierrors.IsNotFound(err) {
+		r.logger.Error(err, "Could not delete child", "Name", name)
+		return err
+	}
+	return nil
+}
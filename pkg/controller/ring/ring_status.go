@@ -0,0 +1,79 @@
+package ring
+
+import (
+	"context"
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// updateStatus records the outcome of the reconciliation on the status subresource of the Ring
+// Conditions specific to a step (eg: IdentityReady) are set by the step itself, this only
+// handles the fields which depend on the overall result
+func (r *ReconcileRing) updateStatus(cr *ringsv1alpha1.Ring, reconcileErr error) error {
+	// The finalizer may already have released the Ring, there is nothing left to report on
+	if cr.GetDeletionTimestamp() != nil {
+		return nil
+	}
+
+	status := &cr.Status
+	status.ObservedGeneration = cr.Generation
+	if reconcileErr != nil {
+		status.LastError = reconcileErr.Error()
+		setCondition(status, ringsv1alpha1.RingDegraded, corev1.ConditionTrue, "ReconcileFailed", reconcileErr.Error())
+		setCondition(status, ringsv1alpha1.RingReady, corev1.ConditionFalse, "ReconcileFailed", reconcileErr.Error())
+	} else {
+		status.LastError = ""
+		setCondition(status, ringsv1alpha1.RingDegraded, corev1.ConditionFalse, "ReconcileSucceeded", "")
+	}
+
+	r.debug.Info("Updating Ring status")
+	if err := r.Client.Status().Update(context.TODO(), cr); err != nil {
+		r.logger.Error(err, "Could not update Ring status")
+		return err
+	}
+	return nil
+}
+
+// setCondition adds or replaces the condition of the given type on the status
+// LastTransitionTime is only moved when the status of the condition changes
+func setCondition(status *ringsv1alpha1.RingStatus, condType ringsv1alpha1.RingConditionType, condStatus corev1.ConditionStatus, reason, message string) {
+	cond := ringsv1alpha1.RingCondition{
+		Type:               condType,
+		Status:             condStatus,
+		LastTransitionTime: metav1.Now(),
+		Reason:             reason,
+		Message:            message,
+	}
+
+	if existing := getCondition(status, condType); existing != nil {
+		if existing.Status == condStatus {
+			cond.LastTransitionTime = existing.LastTransitionTime
+		}
+		*existing = cond
+		return
+	}
+	status.Conditions = append(status.Conditions, cond)
+}
+
+// getCondition returns the condition of the given type from the status, or nil if it is not set
+func getCondition(status *ringsv1alpha1.RingStatus, condType ringsv1alpha1.RingConditionType) *ringsv1alpha1.RingCondition {
+	for i := range status.Conditions {
+		if status.Conditions[i].Type == condType {
+			return &status.Conditions[i]
+		}
+	}
+	return nil
+}
+
+// removeCondition drops the condition of the given type from the status
+func removeCondition(status *ringsv1alpha1.RingStatus, condType ringsv1alpha1.RingConditionType) {
+	conditions := status.Conditions[:0]
+	for _, c := range status.Conditions {
+		if c.Type != condType {
+			conditions = append(conditions, c)
+		}
+	}
+	status.Conditions = conditions
+}
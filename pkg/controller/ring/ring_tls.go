@@ -0,0 +1,228 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	certmanager "github.com/microsoft/ring-operator/pkg/certmanager/v1"
+	traefik "github.com/microsoft/ring-operator/pkg/traefik/v1alpha1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+const tlsSecretName = "%s-tls"
+
+// isCertManagerEnabled reports whether the operator may create cert-manager Certificates
+// The cert-manager CRDs must be installed for the operator to watch them, so it is opt-in
+func isCertManagerEnabled() bool {
+	return strings.ToLower(os.Getenv("RING_CERT_MANAGER_ENABLED")) == "true"
+}
+
+// getTLSSecretName returns the name of the Secret holding the certificate of the ring
+func getTLSSecretName(cr *ringsv1alpha1.Ring) string {
+	tls := cr.Spec.Routing.TLS
+	if tls.SecretName == "" && tls.Certificate != nil {
+		return fmt.Sprintf(tlsSecretName, cr.Name)
+	}
+	return tls.SecretName
+}
+
+// getIngressRouteTLS returns the TLS configuration of the IngressRoute, or nil when the ring has none
+func getIngressRouteTLS(cr *ringsv1alpha1.Ring) *traefik.TLS {
+	tls := cr.Spec.Routing.TLS
+	if tls == nil {
+		return nil
+	}
+
+	ingTLS := &traefik.TLS{
+		SecretName:   getTLSSecretName(cr),
+		CertResolver: tls.CertResolver,
+	}
+	if tls.Options != nil {
+		ingTLS.Options = &traefik.TLSOptionRef{
+			Name:      tls.Options.Name,
+			Namespace: tls.Options.Namespace,
+		}
+	}
+	for _, d := range tls.Domains {
+		ingTLS.Domains = append(ingTLS.Domains, traefik.Domain{Main: d.Main, SANs: d.SANs})
+	}
+	return ingTLS
+}
+
+// validateTLS checks the certificate requested by the ring can be issued by cert-manager
+func validateTLS(routing *ringsv1alpha1.RingRouting) error {
+	if routing.TLS == nil || routing.TLS.Certificate == nil {
+		return nil
+	}
+	if !isCertManagerEnabled() {
+		return errors.New("tls certificate requires cert-manager, set RING_CERT_MANAGER_ENABLED on the operator")
+	}
+	if len(routing.Hosts) == 0 {
+		return errors.New("tls certificate requires the ring to set hosts")
+	}
+	return nil
+}
+
+// reconcileCertificate ensures the cert-manager Certificate requested by the ring exists and records
+// whether it has been issued in status. The Certificate is removed once the ring no longer requests it
+// It returns the name of the Certificate, or an empty name when there is none
+func (r *ReconcileRing) reconcileCertificate(cr *ringsv1alpha1.Ring, status *ringsv1alpha1.RingStatus) (string, error) {
+	tls := cr.Spec.Routing.TLS
+	if tls == nil || tls.Certificate == nil {
+		removeCondition(status, ringsv1alpha1.RingCertificateReady)
+		if !isCertManagerEnabled() {
+			return "", nil
+		}
+		return "", r.deleteCertificate(cr)
+	}
+
+	if err := validateTLS(&cr.Spec.Routing); err != nil {
+		return "", err
+	}
+
+	cert, err := r.createOrUpdateCertificate(cr)
+	if err != nil {
+		return "", err
+	}
+
+	ready := corev1.ConditionFalse
+	reason, message := "Pending", "Certificate has not been issued yet"
+	for _, c := range cert.Status.Conditions {
+		if c.Type == certmanager.CertificateConditionReady {
+			ready, reason, message = c.Status, c.Reason, c.Message
+		}
+	}
+	if reason == "" {
+		reason = "Issued"
+	}
+	setCondition(status, ringsv1alpha1.RingCertificateReady, ready, reason, message)
+	return cert.Name, nil
+}
+
+// getCertificateSpec returns the Certificate the ring requests for its hosts
+func getCertificateSpec(cr *ringsv1alpha1.Ring) certmanager.CertificateSpec {
+	issuer := cr.Spec.Routing.TLS.Certificate
+	kind := issuer.IssuerKind
+	if kind == "" {
+		kind = "Issuer"
+	}
+
+	return certmanager.CertificateSpec{
+		SecretName: getTLSSecretName(cr),
+		DNSNames:   cr.Spec.Routing.Hosts,
+		IssuerRef: certmanager.ObjectReference{
+			Name:  issuer.IssuerName,
+			Kind:  kind,
+			Group: certmanager.GroupName,
+		},
+	}
+}
+
+// newCertificateForCR creates a new cert-manager Certificate object (not yet created) for the ring hosts
+func (r *ReconcileRing) newCertificateForCR(cr *ringsv1alpha1.Ring) *certmanager.Certificate {
+	r.logger.Info("Creating Certificate", "Certificate.Namespace", cr.Namespace, "Certificate.Name", cr.Name)
+
+	objMeta := metav1.ObjectMeta{
+		Name:      cr.Name,
+		Namespace: cr.Namespace,
+		Labels:    cr.ObjectMeta.Labels,
+	}
+
+	return &certmanager.Certificate{
+		ObjectMeta: objMeta,
+		Spec:       getCertificateSpec(cr),
+	}
+}
+
+func (r *ReconcileRing) updateCertificateForCR(cert *certmanager.Certificate, cr *ringsv1alpha1.Ring) *certmanager.Certificate {
+	r.logger.Info("Updating Certificate", "Certificate.Namespace", cr.Namespace, "Certificate.Name", cr.Name)
+	newCert := cert.DeepCopy()
+
+	newCert.Labels = cr.ObjectMeta.Labels
+	newCert.Spec = getCertificateSpec(cr)
+	return newCert
+}
+
+// createOrUpdateCertificate ensures the Certificate exists with the up to date information in the Ring instance
+// It returns created or updated Certificate and any error
+func (r *ReconcileRing) createOrUpdateCertificate(cr *ringsv1alpha1.Ring) (*certmanager.Certificate, error) {
+	r.debug.Info("createOrUpdateCertificate")
+
+	r.logger.Info("Finding Certificate")
+	certFound := &certmanager.Certificate{}
+
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, certFound)
+	if err != nil && apierrors.IsNotFound(err) {
+		cert := r.newCertificateForCR(cr)
+
+		r.debug.Info("Setting Ring as owner of Certificate")
+		if err := controllerutil.SetControllerReference(cr, cert, r.Scheme); err != nil {
+			r.logger.Error(err, "Could not set Ring as owner of Certificate")
+			return nil, err
+		}
+
+		r.logger.Info("Creating a new Certificate")
+		if err = r.Client.Create(context.TODO(), cert); err != nil {
+			r.logger.Error(err, "Could not create Certificate")
+			return nil, err
+		}
+		return cert, nil
+	} else if err != nil {
+		r.logger.Error(err, "Could not get existing Certificate")
+		return nil, err
+	} else {
+		// A Certificate of the same name which the ring doesn't control, created by the user or controlled by
+		// another object, is neither updated nor adopted
+		if !metav1.IsControlledBy(certFound, cr) {
+			reason := errors.New("the Certificate is not controlled by the ring")
+			if owner := metav1.GetControllerOf(certFound); owner != nil {
+				reason = fmt.Errorf("the Certificate is controlled by %s %s", owner.Kind, owner.Name)
+			}
+			err := apierrors.NewConflict(certmanager.SchemeGroupVersion.WithResource("certificates").GroupResource(), cr.Name, reason)
+			r.logger.Error(err, "Certificate is not controlled by the ring")
+			return nil, err
+		}
+
+		r.logger.Info("Updating Certificate")
+
+		cert := r.updateCertificateForCR(certFound, cr)
+		if err = r.Client.Update(context.TODO(), cert); err != nil {
+			r.logger.Info("Could not update Certificate")
+			return nil, err
+		}
+		return cert, nil
+	}
+}
+
+// deleteCertificate removes the Certificate of the ring if it exists
+func (r *ReconcileRing) deleteCertificate(cr *ringsv1alpha1.Ring) error {
+	certFound := &certmanager.Certificate{}
+
+	err := r.Client.Get(context.TODO(), types.NamespacedName{Name: cr.Name, Namespace: cr.Namespace}, certFound)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		r.logger.Error(err, "Could not get existing Certificate")
+		return err
+	}
+
+	if !metav1.IsControlledBy(certFound, cr) {
+		return nil
+	}
+
+	r.logger.Info("Deleting Certificate", "Certificate.Namespace", cr.Namespace, "Certificate.Name", cr.Name)
+	if err := r.Client.Delete(context.TODO(), certFound); err != nil && !apierrors.IsNotFound(err) {
+		r.logger.Error(err, "Could not delete Certificate")
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+package ring
+
+import (
+	"net"
+	"regexp"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateRing checks the ring can be reconciled and routed by the router and returns every reason it can't
+func ValidateRing(cr *ringsv1alpha1.Ring, router Router) field.ErrorList {
+	errs := field.ErrorList{}
+	routing := &cr.Spec.Routing
+	path := field.NewPath("spec", "routing")
+
+	// The service, version and branch select the deployments of the ring
+	labels := []struct {
+		name, value string
+	}{
+		{"service", routing.Service},
+		{"version", routing.Version},
+		{"branch", routing.Branch},
+	}
+	for _, l := range labels {
+		if l.value == "" {
+			errs = append(errs, field.Required(path.Child(l.name), ""))
+			continue
+		}
+		for _, msg := range validation.IsValidLabelValue(l.value) {
+			errs = append(errs, field.Invalid(path.Child(l.name), l.value, msg))
+		}
+	}
+
+	errs = append(errs, validatePorts(routing, path.Child("ports"))...)
+	errs = append(errs, validateGroups(routing, path)...)
+	errs = append(errs, validateMatchers(routing, path.Child("matchers"))...)
+	errs = append(errs, validateHosts(routing, path.Child("hosts"))...)
+
+	// The parts of the ring checked again while reconciling
+	if err := validateSplit(routing.Split); err != nil {
+		errs = append(errs, field.Invalid(path.Child("split"), routing.Split, err.Error()))
+	}
+	if err := validatePath(routing); err != nil {
+		errs = append(errs, field.Invalid(path.Child("path"), routing.Path, err.Error()))
+	}
+	if err := validateRateLimit(routing.RateLimit); err != nil {
+		errs = append(errs, field.Invalid(path.Child("rateLimit"), routing.RateLimit, err.Error()))
+	}
+	if err := validateMiddlewares(cr); err != nil {
+		errs = append(errs, field.Invalid(path.Child("middlewares"), routing.Middlewares, err.Error()))
+	}
+	if err := validateTLS(routing); err != nil {
+		errs = append(errs, field.Invalid(path.Child("tls", "certificate"), routing.TLS.Certificate, err.Error()))
+	}
+	if rollout := cr.Spec.Rollout; rollout != nil {
+		if err := validateRollout(rollout); err != nil {
+			errs = append(errs, field.Invalid(field.NewPath("spec", "rollout"), rollout, err.Error()))
+		}
+	}
+	return append(errs, router.Validate(cr)...)
+}
+
+// ValidateRingUpdate checks the update of the ring leaves its immutable fields untouched
+// The service, version and branch name the children of the ring and can't be moved to other deployments
+func ValidateRingUpdate(cr, old *ringsv1alpha1.Ring) field.ErrorList {
+	errs := field.ErrorList{}
+	path := field.NewPath("spec", "routing")
+
+	immutable := []struct {
+		name            string
+		value, oldValue string
+	}{
+		{"service", cr.Spec.Routing.Service, old.Spec.Routing.Service},
+		{"version", cr.Spec.Routing.Version, old.Spec.Routing.Version},
+		{"branch", cr.Spec.Routing.Branch, old.Spec.Routing.Branch},
+	}
+	for _, f := range immutable {
+		if f.value != f.oldValue {
+			errs = append(errs, field.Forbidden(path.Child(f.name), "field is immutable"))
+		}
+	}
+	return errs
+}
+
+func validatePorts(routing *ringsv1alpha1.RingRouting, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	if len(routing.Ports) == 0 {
+		return append(errs, field.Required(path, "at least one port must be exposed"))
+	}
+
+	names := map[string]bool{}
+	for i, port := range routing.Ports {
+		if port.Name == "" && len(routing.Ports) > 1 {
+			errs = append(errs, field.Required(path.Index(i).Child("name"), "ports must be named when there are several"))
+		}
+		if port.Name != "" {
+			for _, msg := range validation.IsDNS1123Label(port.Name) {
+				errs = append(errs, field.Invalid(path.Index(i).Child("name"), port.Name, msg))
+			}
+			if names[port.Name] {
+				errs = append(errs, field.Duplicate(path.Index(i).Child("name"), port.Name))
+			}
+			names[port.Name] = true
+		}
+		for _, msg := range validation.IsValidPortNum(int(port.Port)) {
+			errs = append(errs, field.Invalid(path.Index(i).Child("port"), port.Port, msg))
+		}
+	}
+	return errs
+}
+
+// validateGroups checks the group names can be quoted in a Traefik rule and created as AAD groups
+func validateGroups(routing *ringsv1alpha1.RingRouting, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	check := func(p *field.Path, name string) {
+		if strings.ContainsAny(name, "`\"") {
+			errs = append(errs, field.Invalid(p, name, "must not contain quotes or backticks"))
+		}
+	}
+
+	if routing.Group.Name != "" {
+		check(path.Child("group", "name"), routing.Group.Name)
+	}
+	for i, g := range routing.Groups {
+		p := path.Child("groups").Index(i).Child("name")
+		if g.Name == "" {
+			errs = append(errs, field.Required(p, ""))
+			continue
+		}
+		check(p, g.Name)
+	}
+
+	if len(getRingGroups(routing)) == 0 && len(routing.Matchers) == 0 {
+		errs = append(errs, field.Required(path.Child("groups"), "the ring must target at least one group or matcher"))
+	}
+	return errs
+}
+
+func validateMatchers(routing *ringsv1alpha1.RingRouting, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, m := range routing.Matchers {
+		p := path.Index(i)
+		if m.Type != ringsv1alpha1.MatchClientIP && m.Name == "" {
+			errs = append(errs, field.Required(p.Child("name"), ""))
+		}
+		if m.Value == "" {
+			errs = append(errs, field.Required(p.Child("value"), ""))
+			continue
+		}
+
+		switch m.Type {
+		case ringsv1alpha1.MatchHeader, ringsv1alpha1.MatchCookie, ringsv1alpha1.MatchQuery:
+		case ringsv1alpha1.MatchHeaderRegex:
+			if _, err := regexp.Compile(m.Value); err != nil {
+				errs = append(errs, field.Invalid(p.Child("value"), m.Value, err.Error()))
+			}
+		case ringsv1alpha1.MatchClientIP:
+			if _, _, err := net.ParseCIDR(m.Value); err != nil && net.ParseIP(m.Value) == nil {
+				errs = append(errs, field.Invalid(p.Child("value"), m.Value, "must be an IP address or a CIDR range"))
+			}
+		default:
+			errs = append(errs, field.NotSupported(p.Child("type"), m.Type, []string{
+				string(ringsv1alpha1.MatchHeader),
+				string(ringsv1alpha1.MatchHeaderRegex),
+				string(ringsv1alpha1.MatchCookie),
+				string(ringsv1alpha1.MatchQuery),
+				string(ringsv1alpha1.MatchClientIP),
+			}))
+		}
+	}
+	return errs
+}
+
+func validateHosts(routing *ringsv1alpha1.RingRouting, path *field.Path) field.ErrorList {
+	errs := field.ErrorList{}
+	for i, h := range routing.Hosts {
+		for _, msg := range validation.IsDNS1123Subdomain(strings.ToLower(strings.TrimPrefix(h, "*."))) {
+			errs = append(errs, field.Invalid(path.Index(i), h, msg))
+		}
+	}
+	return errs
+}
@@ -0,0 +1,102 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// getRingBranches returns the branches whose deployments receive the traffic of the ring
+func getRingBranches(routing *ringsv1alpha1.RingRouting) []string {
+	if len(routing.Split) == 0 {
+		return []string{routing.Branch}
+	}
+	branches := make([]string, len(routing.Split))
+	for i, b := range routing.Split {
+		branches[i] = b.Branch
+	}
+	return branches
+}
+
+// verifyWorkloads checks a Deployment whose pods are selected for every branch of the ring exists and that it
+// exposes every target port of the ring, by number or name
+// It returns the reason and the problems found, the ring is still routed as the workloads may not be deployed yet
+func (r *ReconcileRing) verifyWorkloads(cr *ringsv1alpha1.Ring) (string, []string, error) {
+	deployments := &appsv1.DeploymentList{}
+	if err := r.Client.List(context.TODO(), client.InNamespace(cr.Namespace), deployments); err != nil {
+		r.logger.Error(err, "Could not list Deployments")
+		return "", nil, err
+	}
+
+	routing := &cr.Spec.Routing
+	reason, problems := "PortNotExposed", []string{}
+	for _, branch := range getRingBranches(routing) {
+		selector := labels.SelectorFromSet(getServiceSelector(routing, branch))
+		found := false
+		for _, d := range deployments.Items {
+			if !selector.Matches(labels.Set(d.Spec.Template.Labels)) {
+				continue
+			}
+			found = true
+			for _, port := range routing.Ports {
+				if !exposesPort(&d.Spec.Template.Spec, port.TargetPort) {
+					problems = append(problems, fmt.Sprintf("port %s not exposed by deployment %s", port.TargetPort.String(), d.Name))
+				}
+			}
+		}
+
+		if !found {
+			reason = "WorkloadNotFound"
+			problems = append(problems, fmt.Sprintf("no workload found for service %s version %s branch %s",
+				routing.Service, routing.Version, branch))
+		}
+	}
+	return reason, problems, nil
+}
+
+// exposesPort returns whether a container of the pod declares the port, by number or name
+func exposesPort(pod *corev1.PodSpec, port intstr.IntOrString) bool {
+	for _, c := range pod.Containers {
+		for _, p := range c.Ports {
+			if port.Type == intstr.String && p.Name == port.StrVal {
+				return true
+			}
+			if port.Type == intstr.Int && p.ContainerPort == port.IntVal {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// reportWorkloads records the outcome of the workload verification on the status, with a Warning Event
+// when new problems are found
+func (r *ReconcileRing) reportWorkloads(cr *ringsv1alpha1.Ring, status *ringsv1alpha1.RingStatus, reason string, problems []string) {
+	if len(problems) == 0 {
+		setCondition(status, ringsv1alpha1.RingWorkloadVerified, corev1.ConditionTrue, "Verified", "")
+		return
+	}
+
+	message := strings.Join(problems, ", ")
+
+	// Events are only recorded when the problems change so a ring is not flooded on every reconciliation
+	if previous := getCondition(status, ringsv1alpha1.RingWorkloadVerified); previous == nil || previous.Message != message {
+		r.recordEvent(cr, corev1.EventTypeWarning, reason, message)
+	}
+	setCondition(status, ringsv1alpha1.RingWorkloadVerified, corev1.ConditionFalse, reason, message)
+}
+
+// recordEvent records an Event on the ring when the reconciler has a recorder
+func (r *ReconcileRing) recordEvent(cr *ringsv1alpha1.Ring, eventType, reason, message string) {
+	if r.Recorder != nil {
+		r.Recorder.Event(cr, eventType, reason, message)
+	}
+}
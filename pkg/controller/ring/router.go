@@ -0,0 +1,570 @@
+package ring
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+)
+
+// defaultRouterName is the router of the operators which don't set RING_ROUTER
+const defaultRouterName = traefikRouterName
+
+// Router produces the objects routing the requests of a ring through an ingress controller
+// The Services of the ring branches are created by the reconciler for every router
+type Router interface {
+	// Name of the router, as set in RING_ROUTER
+	Name() string
+	// AddToScheme registers the types of the routing objects
+	AddToScheme(s *runtime.Scheme) error
+	// OwnedTypes returns the types of the routing objects, they are watched and the ones a ring
+	// no longer needs are removed
+	OwnedTypes() []OwnedType
+	// Validate returns the parts of the ring the router can't route
+	Validate(cr *ringsv1alpha1.Ring) field.ErrorList
+	// Route returns the desired routing objects of the ring
+	Route(cr *ringsv1alpha1.Ring) (*Routing, error)
+}
+
+// OwnedType is a type of routing object along with its list type
+type OwnedType struct {
+	Object runtime.Object
+	List   runtime.Object
+	// Shared objects are owned by every ring routed through them instead of being controlled by one ring
+	Shared bool
+}
+
+// Routing is the routing of a ring produced by a Router, its objects are owned by the ring
+type Routing struct {
+	// Route is the object sending the requests of the ring to its Services, it is removed while the
+	// requests of the ring fall back to the production ring
+	Route runtime.Object
+	// Objects are the other objects the Route refers to, they are created before it
+	Objects []runtime.Object
+	// Match is the rule of the Route matching the requests of the ring, left empty by the routers whose
+	// objects have no rule
+	Match string
+	// MiddlewareNames are the names of the objects the requests of the ring go through, in order
+	MiddlewareNames []string
+}
+
+// RouteStatusReporter is implemented by the routers whose route reports whether the ingress controller accepted it
+type RouteStatusReporter interface {
+	// RouteAccepted returns the status, reason and message of the acceptance of the route found in the cluster
+	RouteAccepted(route runtime.Object) (corev1.ConditionStatus, string, string)
+}
+
+// SharedRouter is implemented by the routers merging the routing of several rings into shared objects
+// The shared objects are owned by every ring routed through them and removed along with the last one
+type SharedRouter interface {
+	// SharesRouting reports whether both rings are routed through the same shared objects
+	SharesRouting(a, b *ringsv1alpha1.Ring) bool
+	// RouteShared returns the shared objects of the ring routing the given rings, the ring is among them
+	// when it is routed
+	// The first object is the one routing the requests of the ring when it is routed, the objects the ring
+	// owned which are no longer returned are removed
+	RouteShared(cr *ringsv1alpha1.Ring, rings []*ringsv1alpha1.Ring) ([]runtime.Object, error)
+}
+
+// RouteLimiter is implemented by the routers which can't route some rings alongside each other
+type RouteLimiter interface {
+	// LimitRoute returns why the ring can't be routed alongside the other ring, or an empty string when both
+	// can be routed
+	// otherFirst reports whether the other ring claimed its route first, when it did it keeps its route
+	LimitRoute(cr, other *ringsv1alpha1.Ring, otherFirst bool) string
+}
+
+// APIDetector is implemented by the routers whose routing objects depend on the API groups served by the cluster
+// It is called once at startup, before the router is added to the scheme of the operator
+type APIDetector interface {
+	DetectAPIs(d discovery.DiscoveryInterface) error
+}
+
+// LegacyMigrator is implemented by the routers which move the routing objects of the rings out of the API groups
+// they no longer produce, it is called once at startup before the rings are reconciled with the watched namespace,
+// empty for every namespace
+type LegacyMigrator interface {
+	MigrateLegacy(c client.Client, namespace string) error
+}
+
+// routerAnnotationPrefixes are the prefixes of the annotations configuring routing objects, the operator
+// owns them instead of keeping the ones found on the existing objects
+var routerAnnotationPrefixes = []string{nginxAnnotationPrefix}
+
+// routers creates the routers the operator can be configured with by name, from the operator settings
+var routers = map[string]func() (Router, error){
+	traefikRouterName: newTraefikRouter,
+	gatewayRouterName: newGatewayRouter,
+	istioRouterName:   newIstioRouter,
+	nginxRouterName:   newNginxRouter,
+	smiRouterName:     newSMIRouter,
+}
+
+// NewRouter returns the router with the given name
+func NewRouter(name string) (Router, error) {
+	newRouter, ok := routers[name]
+	if !ok {
+		names := []string{}
+		for n := range routers {
+			names = append(names, n)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unknown router %q, expected one of %s", name, strings.Join(names, ", "))
+	}
+	return newRouter()
+}
+
+// newRouterFromEnv returns the router selected by RING_ROUTER, Traefik when it is not set
+func newRouterFromEnv() (Router, error) {
+	name := strings.ToLower(strings.TrimSpace(os.Getenv("RING_ROUTER")))
+	if name == "" {
+		name = defaultRouterName
+	}
+	return NewRouter(name)
+}
+
+// NewRouterForConfig returns the router selected by RING_ROUTER with the APIs served by the cluster of cfg detected,
+// the webhook validates the rings with it as the controller routes them
+func NewRouterForConfig(cfg *rest.Config) (Router, error) {
+	router, err := newRouterFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	if detector, ok := router.(APIDetector); ok {
+		dc, err := discovery.NewDiscoveryClientForConfig(cfg)
+		if err != nil {
+			return nil, err
+		}
+		if err := detector.DetectAPIs(dc); err != nil {
+			return nil, fmt.Errorf("could not detect the APIs served for router %s: %v", router.Name(), err)
+		}
+	}
+	return router, nil
+}
+
+// router returns the router of the reconciler, the one selected by RING_ROUTER when it has none
+func (r *ReconcileRing) router() (Router, error) {
+	if r.Router != nil {
+		return r.Router, nil
+	}
+	return newRouterFromEnv()
+}
+
+// reconcileRouting ensures the routing objects of the ring exist, the Route only when the ring is routed,
+// and removes the ones it no longer uses
+func (r *ReconcileRing) reconcileRouting(cr *ringsv1alpha1.Ring, router Router, routing *Routing, routed bool) error {
+	keep := map[string]bool{}
+	for _, obj := range routing.Objects {
+		if err := r.createOrUpdateOwned(cr, obj); err != nil {
+			return err
+		}
+		keep[getOwnedKey(obj)] = true
+	}
+
+	if routed && routing.Route != nil {
+		if err := r.createOrUpdateOwned(cr, routing.Route); err != nil {
+			return err
+		}
+		keep[getOwnedKey(routing.Route)] = true
+	}
+
+	r.debug.Info("Removing routing objects no longer used by the ring")
+	return r.pruneOwned(cr, router, keep)
+}
+
+// createOrUpdateOwned ensures the object exists, controlled by the ring, with the desired labels and spec
+// An object of the same name controlled by another object, such as the object of another ring whose name
+// matches the name of this one, is not taken over
+func (r *ReconcileRing) createOrUpdateOwned(cr *ringsv1alpha1.Ring, obj runtime.Object) error {
+	return r.createOrUpdate(obj, func(desired, existing metav1.Object) error {
+		if existing != nil {
+			if owner := metav1.GetControllerOf(existing); owner != nil && !metav1.IsControlledBy(existing, cr) {
+				return apierrors.NewConflict(r.getGroupResource(obj), desired.GetName(), fmt.Errorf("the object is controlled by %s %s", owner.Kind, owner.Name))
+			}
+		}
+		return controllerutil.SetControllerReference(cr, desired, r.Scheme)
+	})
+}
+
+// getGroupResource returns the group and resource of the object reported in conflicts
+func (r *ReconcileRing) getGroupResource(obj runtime.Object) schema.GroupResource {
+	if gvks, _, err := r.Scheme.ObjectKinds(obj); err == nil {
+		return schema.GroupResource{Group: gvks[0].Group, Resource: strings.ToLower(gvks[0].Kind)}
+	}
+	return schema.GroupResource{}
+}
+
+// createOrUpdate ensures the object exists with the desired labels, spec and the owners set by setOwner, which
+// is given the existing object or nil when it is created
+// The annotations set on an existing object by others are kept, along with the desired ones
+func (r *ReconcileRing) createOrUpdate(obj runtime.Object, setOwner func(desired, existing metav1.Object) error) error {
+	desired, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+	kind := fmt.Sprintf("%T", obj)
+
+	found := obj.DeepCopyObject()
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, found)
+	if err != nil && !apierrors.IsNotFound(err) {
+		r.logger.Error(err, "Could not get existing child", "Kind", kind, "Name", desired.GetName())
+		return err
+	}
+	notFound := apierrors.IsNotFound(err)
+
+	var existing metav1.Object
+	if !notFound {
+		if existing, err = meta.Accessor(found); err != nil {
+			return err
+		}
+	}
+	if err := setOwner(desired, existing); err != nil {
+		r.logger.Error(err, "Could not set Ring as owner of child", "Kind", kind, "Name", desired.GetName())
+		return err
+	}
+
+	if notFound {
+		r.logger.Info("Creating child", "Kind", kind, "Namespace", desired.GetNamespace(), "Name", desired.GetName())
+		if err := r.Client.Create(context.TODO(), obj); err != nil {
+			r.logger.Error(err, "Could not create child", "Kind", kind, "Name", desired.GetName())
+			return err
+		}
+		return nil
+	}
+
+	desired.SetResourceVersion(existing.GetResourceVersion())
+	desired.SetAnnotations(mergeAnnotations(desired.GetAnnotations(), existing.GetAnnotations()))
+	if err := keepStatus(obj, found); err != nil {
+		return err
+	}
+	// The cluster IP of a Service is allocated by the API server and can't be changed
+	if svc, ok := obj.(*corev1.Service); ok {
+		svc.Spec.ClusterIP = found.(*corev1.Service).Spec.ClusterIP
+	}
+
+	r.logger.Info("Updating child", "Kind", kind, "Namespace", desired.GetNamespace(), "Name", desired.GetName())
+	if err := r.Client.Update(context.TODO(), obj); err != nil {
+		r.logger.Error(err, "Could not update child", "Kind", kind, "Name", desired.GetName())
+		return err
+	}
+	return nil
+}
+
+// pruneOwned deletes the routing objects controlled by the ring which are not in the keep set
+// The shared routing objects are left to reconcileSharedRouting
+func (r *ReconcileRing) pruneOwned(cr *ringsv1alpha1.Ring, router Router, keep map[string]bool) error {
+	for _, t := range router.OwnedTypes() {
+		if t.Shared {
+			continue
+		}
+		list := t.List.DeepCopyObject()
+		if err := r.Client.List(context.TODO(), client.InNamespace(cr.Namespace), list); err != nil {
+			r.logger.Error(err, "Could not list children", "Kind", fmt.Sprintf("%T", t.List))
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			obj, err := meta.Accessor(item)
+			if err != nil {
+				return err
+			}
+			if keep[getOwnedKey(item)] || !metav1.IsControlledBy(obj, cr) {
+				continue
+			}
+
+			r.logger.Info("Deleting child", "Kind", fmt.Sprintf("%T", item), "Namespace", cr.Namespace, "Name", obj.GetName())
+			if err := r.Client.Delete(context.TODO(), item); err != nil && !apierrors.IsNotFound(err) {
+				r.logger.Error(err, "Could not delete child", "Name", obj.GetName())
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// mergeAnnotations returns the desired annotations along with the existing ones which don't configure the routing
+func mergeAnnotations(desired, existing map[string]string) map[string]string {
+	merged := map[string]string{}
+	for k, v := range existing {
+		owned := false
+		for _, prefix := range routerAnnotationPrefixes {
+			owned = owned || strings.HasPrefix(k, prefix)
+		}
+		if !owned {
+			merged[k] = v
+		}
+	}
+	for k, v := range desired {
+		merged[k] = v
+	}
+	if len(merged) == 0 {
+		return nil
+	}
+	return merged
+}
+
+// keepStatus copies the status of the existing object to the desired one, the status is written by the
+// ingress controller and would be lost when updating the types without a status subresource
+func keepStatus(desired, existing runtime.Object) error {
+	from, err := runtime.DefaultUnstructuredConverter.ToUnstructured(existing)
+	if err != nil {
+		return err
+	}
+	status, ok := from["status"]
+	if !ok {
+		return nil
+	}
+
+	to, err := runtime.DefaultUnstructuredConverter.ToUnstructured(desired)
+	if err != nil {
+		return err
+	}
+	to["status"] = status
+	return runtime.DefaultUnstructuredConverter.FromUnstructured(to, desired)
+}
+
+// getOwnedKey identifies a routing object by type and name
+func getOwnedKey(obj runtime.Object) string {
+	name := ""
+	if m, err := meta.Accessor(obj); err == nil {
+		name = m.GetName()
+	}
+	return fmt.Sprintf("%T/%s", obj, name)
+}
+
+// routeWaitingError is returned by the routers which can't route the ring before another ring is routed, the ring
+// waits for it instead of failing
+type routeWaitingError struct {
+	reason  string
+	message string
+}
+
+func (e *routeWaitingError) Error() string {
+	return e.message
+}
+
+// reconcileSharedRouting ensures the objects the ring shares with the other rings of its router route the
+// rings of the group which are routed, the ring only when routed is set, and returns the name of the
+// object routing its requests
+// The shared objects are removed once none of their rings is routed, along with the ones the ring owned
+// which are no longer rendered
+func (r *ReconcileRing) reconcileSharedRouting(cr *ringsv1alpha1.Ring, router Router, routed bool) (string, error) {
+	shared, ok := router.(SharedRouter)
+	if !ok {
+		return "", nil
+	}
+
+	ringList := &ringsv1alpha1.RingList{}
+	if err := r.Client.List(context.TODO(), client.InNamespace(cr.Namespace), ringList); err != nil {
+		r.logger.Error(err, "Could not list Rings")
+		return "", err
+	}
+
+	rings := []*ringsv1alpha1.Ring{}
+	for i := range ringList.Items {
+		other := &ringList.Items[i]
+		if other.Name == cr.Name || !shared.SharesRouting(cr, other) {
+			continue
+		}
+		otherRouted, err := r.isRingRouted(other, router)
+		if err != nil {
+			return "", err
+		}
+		if otherRouted {
+			rings = append(rings, getDesiredRing(other))
+		}
+	}
+	if routed && cr.DeletionTimestamp == nil {
+		rings = append(rings, cr)
+	}
+
+	objs, err := shared.RouteShared(cr, rings)
+	if _, ok := err.(*routeWaitingError); ok {
+		// The shared objects of the ring are removed until it can be routed
+		if err := r.pruneShared(cr, router, map[string]bool{}); err != nil {
+			return "", err
+		}
+		return "", err
+	}
+	if err != nil {
+		return "", err
+	}
+	keep := map[string]bool{}
+	for _, obj := range objs {
+		if len(rings) == 0 {
+			err = r.deleteShared(obj)
+		} else {
+			err = r.createOrUpdateShared(obj, rings)
+		}
+		if err != nil {
+			return "", err
+		}
+		keep[getOwnedKey(obj)] = len(rings) > 0
+	}
+	if err := r.pruneShared(cr, router, keep); err != nil {
+		return "", err
+	}
+
+	if len(objs) == 0 || !routed {
+		return "", nil
+	}
+	route, err := meta.Accessor(objs[0])
+	if err != nil {
+		return "", err
+	}
+	return route.GetName(), nil
+}
+
+// createOrUpdateShared ensures the shared routing object exists, owned by the rings sharing it
+// An object of the same name which isn't shared by rings alone, such as the Service of the application named
+// after its service or an object controlled by another ring, is not taken over
+func (r *ReconcileRing) createOrUpdateShared(obj runtime.Object, rings []*ringsv1alpha1.Ring) error {
+	return r.createOrUpdate(obj, func(desired, existing metav1.Object) error {
+		if existing != nil && !isSharedByRings(existing) {
+			reason := errors.New("the object is not shared by rings")
+			if owner := metav1.GetControllerOf(existing); owner != nil {
+				reason = fmt.Errorf("the object is controlled by %s %s", owner.Kind, owner.Name)
+			}
+			return apierrors.NewConflict(r.getGroupResource(obj), desired.GetName(), reason)
+		}
+		desired.SetOwnerReferences(getSharedOwnerReferences(rings))
+		return nil
+	})
+}
+
+// isSharedByRings reports whether the object is owned by rings only, none of them controlling it
+func isSharedByRings(obj metav1.Object) bool {
+	refs := obj.GetOwnerReferences()
+	for _, ref := range refs {
+		if ref.Kind != "Ring" || ref.APIVersion != ringsv1alpha1.SchemeGroupVersion.String() || (ref.Controller != nil && *ref.Controller) {
+			return false
+		}
+	}
+	return len(refs) > 0
+}
+
+// isRingRouted reports whether the reconciliation of the ring routes it
+// The rings sharing routing objects are evaluated from the cluster rather than from their status so the shared
+// objects don't depend on the order the rings are reconciled in
+func (r *ReconcileRing) isRingRouted(cr *ringsv1alpha1.Ring, router Router) (bool, error) {
+	if !isRingActive(cr) || cr.DeletionTimestamp != nil {
+		return false, nil
+	}
+
+	desired := getDesiredRing(cr)
+	if errs := ValidateRing(desired, router); len(errs) > 0 {
+		return false, nil
+	}
+	if other, err := r.findRouteConflict(desired); err != nil || other != nil {
+		return false, err
+	}
+	return r.checkEndpoints(desired, desired.Status.DeepCopy())
+}
+
+// pruneShared deletes the shared routing objects owned by the ring which are not in the keep set
+func (r *ReconcileRing) pruneShared(cr *ringsv1alpha1.Ring, router Router, keep map[string]bool) error {
+	for _, t := range router.OwnedTypes() {
+		if !t.Shared {
+			continue
+		}
+		list := t.List.DeepCopyObject()
+		if err := r.Client.List(context.TODO(), client.InNamespace(cr.Namespace), list); err != nil {
+			r.logger.Error(err, "Could not list shared children", "Kind", fmt.Sprintf("%T", t.List))
+			return err
+		}
+		items, err := meta.ExtractList(list)
+		if err != nil {
+			return err
+		}
+
+		for _, item := range items {
+			obj, err := meta.Accessor(item)
+			if err != nil {
+				return err
+			}
+			owned := false
+			for _, ref := range obj.GetOwnerReferences() {
+				owned = owned || (ref.Kind == "Ring" && ref.Name == cr.Name && ref.UID == cr.UID)
+			}
+			if keep[getOwnedKey(item)] || !owned {
+				continue
+			}
+
+			r.logger.Info("Deleting shared child", "Kind", fmt.Sprintf("%T", item), "Namespace", cr.Namespace, "Name", obj.GetName())
+			if err := r.Client.Delete(context.TODO(), item); err != nil && !apierrors.IsNotFound(err) {
+				r.logger.Error(err, "Could not delete shared child", "Name", obj.GetName())
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// deleteShared deletes the shared routing object if it is shared by rings
+func (r *ReconcileRing) deleteShared(obj runtime.Object) error {
+	desired, err := meta.Accessor(obj)
+	if err != nil {
+		return err
+	}
+
+	found := obj.DeepCopyObject()
+	err = r.Client.Get(context.TODO(), types.NamespacedName{Name: desired.GetName(), Namespace: desired.GetNamespace()}, found)
+	if err != nil && apierrors.IsNotFound(err) {
+		return nil
+	} else if err != nil {
+		r.logger.Error(err, "Could not get existing child", "Name", desired.GetName())
+		return err
+	}
+
+	existing, err := meta.Accessor(found)
+	if err != nil {
+		return err
+	}
+	if !isSharedByRings(existing) {
+		return nil
+	}
+
+	r.logger.Info("Deleting shared child", "Kind", fmt.Sprintf("%T", found), "Namespace", existing.GetNamespace(), "Name", existing.GetName())
+	if err := r.Client.Delete(context.TODO(), found); err != nil && !apierrors.IsNotFound(err) {
+		r.logger.Error(err, "Could not delete shared child", "Name", existing.GetName())
+		return err
+	}
+	return nil
+}
+
+// getSharedOwnerReferences returns the owner references of the rings sharing a routing object, none of them
+// controls it
+func getSharedOwnerReferences(rings []*ringsv1alpha1.Ring) []metav1.OwnerReference {
+	refs := make([]metav1.OwnerReference, len(rings))
+	for i, ring := range rings {
+		refs[i] = metav1.OwnerReference{
+			APIVersion: ringsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Ring",
+			Name:       ring.Name,
+			UID:        ring.UID,
+		}
+	}
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name < refs[j].Name })
+	return refs
+}
@@ -0,0 +1,315 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	gatewayv1 "github.com/microsoft/ring-operator/pkg/gatewayapi/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const gatewayRouterName = "gateway"
+
+// gatewayRouter routes the rings with Gateway API HTTPRoutes attached to the Gateway set in RING_GATEWAY
+type gatewayRouter struct {
+	gateway types.NamespacedName
+}
+
+func newGatewayRouter() (Router, error) {
+	gateway := strings.TrimSpace(os.Getenv("RING_GATEWAY"))
+	if gateway == "" {
+		return nil, errors.New("the gateway router requires RING_GATEWAY, the Gateway the rings attach to as name or namespace/name")
+	}
+
+	// The Gateway is looked up in the namespace of the rings when RING_GATEWAY has none
+	ref := types.NamespacedName{Name: gateway}
+	if parts := strings.SplitN(gateway, "/", 2); len(parts) == 2 {
+		ref = types.NamespacedName{Namespace: parts[0], Name: parts[1]}
+	}
+	return &gatewayRouter{gateway: ref}, nil
+}
+
+func (g *gatewayRouter) Name() string {
+	return gatewayRouterName
+}
+
+func (g *gatewayRouter) AddToScheme(s *runtime.Scheme) error {
+	return gatewayv1.AddToScheme(s)
+}
+
+func (g *gatewayRouter) OwnedTypes() []OwnedType {
+	return []OwnedType{
+		{Object: &gatewayv1.HTTPRoute{}, List: &gatewayv1.HTTPRouteList{}},
+	}
+}
+
+// Validate rejects the parts of the ring implemented with Traefik Middlewares and options, which have
+// no HTTPRoute equivalent
+func (g *gatewayRouter) Validate(cr *ringsv1alpha1.Ring) field.ErrorList {
+	errs := field.ErrorList{}
+	routing := &cr.Spec.Routing
+	path := field.NewPath("spec", "routing")
+
+	for i, m := range routing.Matchers {
+		if m.Type == ringsv1alpha1.MatchClientIP {
+			errs = append(errs, field.NotSupported(path.Child("matchers").Index(i).Child("type"), m.Type,
+				[]string{string(ringsv1alpha1.MatchHeader), string(ringsv1alpha1.MatchHeaderRegex), string(ringsv1alpha1.MatchCookie), string(ringsv1alpha1.MatchQuery)}))
+		}
+	}
+	if getPathRewrite(routing) == ringsv1alpha1.RewriteReplace {
+		errs = append(errs, field.NotSupported(path.Child("path", "rewrite"), routing.Path.Rewrite,
+			[]string{string(ringsv1alpha1.RewriteStrip), string(ringsv1alpha1.RewriteKeep)}))
+	}
+	if routing.RateLimit != nil {
+		errs = append(errs, field.Forbidden(path.Child("rateLimit"), "rate limits are not supported by the gateway router"))
+	}
+	if len(routing.Middlewares) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("middlewares"), "middlewares are not supported by the gateway router"))
+	}
+	if tls := routing.TLS; tls != nil {
+		// TLS is terminated by the listeners of the Gateway, which may reference the Secret of the ring
+		if tls.CertResolver != "" || tls.Options != nil || len(tls.Domains) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("tls"), "only secretName and certificate are supported by the gateway router"))
+		}
+	}
+	return errs
+}
+
+// Route returns the HTTPRoute of the ring
+func (g *gatewayRouter) Route(cr *ringsv1alpha1.Ring) (*Routing, error) {
+	routing := &cr.Spec.Routing
+	route := &gatewayv1.HTTPRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels:    cr.ObjectMeta.Labels,
+		},
+		Spec: gatewayv1.HTTPRouteSpec{
+			ParentRefs: g.getParentRefs(routing),
+			Hostnames:  routing.Hosts,
+			Rules: []gatewayv1.HTTPRouteRule{
+				{
+					Matches:     getHTTPRouteMatches(routing),
+					Filters:     getHTTPRouteFilters(cr),
+					BackendRefs: getHTTPBackendRefs(cr),
+				},
+			},
+		},
+	}
+	return &Routing{Route: route}, nil
+}
+
+// RouteAccepted reports whether the Gateways the HTTPRoute is attached to accepted it
+func (g *gatewayRouter) RouteAccepted(obj runtime.Object) (corev1.ConditionStatus, string, string) {
+	route, ok := obj.(*gatewayv1.HTTPRoute)
+	if !ok {
+		return corev1.ConditionUnknown, "Pending", "Route has not been accepted by a Gateway yet"
+	}
+
+	accepted, reason, rejected := 0, "", []string{}
+	for _, p := range route.Status.Parents {
+		for _, c := range p.Conditions {
+			if c.Type != gatewayv1.RouteConditionAccepted {
+				continue
+			}
+			if c.Status == string(corev1.ConditionTrue) {
+				accepted++
+				continue
+			}
+			reason = c.Reason
+			rejected = append(rejected, fmt.Sprintf("gateway %s: %s", getParentName(p.ParentRef, route.Namespace), c.Message))
+		}
+	}
+
+	if len(rejected) > 0 {
+		if reason == "" {
+			reason = "NotAccepted"
+		}
+		return corev1.ConditionFalse, reason, strings.Join(rejected, ", ")
+	}
+	if accepted == 0 {
+		return corev1.ConditionUnknown, "Pending", "Route has not been accepted by a Gateway yet"
+	}
+	return corev1.ConditionTrue, "Accepted", ""
+}
+
+// getParentRefs attaches the route to the Gateway, on the listeners named after the entrypoints of the ring
+// when it sets some
+func (g *gatewayRouter) getParentRefs(routing *ringsv1alpha1.RingRouting) []gatewayv1.ParentReference {
+	newRef := func() gatewayv1.ParentReference {
+		ref := gatewayv1.ParentReference{Name: g.gateway.Name}
+		if g.gateway.Namespace != "" {
+			namespace := g.gateway.Namespace
+			ref.Namespace = &namespace
+		}
+		return ref
+	}
+
+	if len(routing.EntryPoints) == 0 {
+		return []gatewayv1.ParentReference{newRef()}
+	}
+
+	refs := make([]gatewayv1.ParentReference, len(routing.EntryPoints))
+	for i, e := range routing.EntryPoints {
+		section := e
+		refs[i] = newRef()
+		refs[i].SectionName = &section
+	}
+	return refs
+}
+
+// getParentName returns the namespaced name of the parent of a route
+func getParentName(ref gatewayv1.ParentReference, namespace string) string {
+	if ref.Namespace != nil {
+		namespace = *ref.Namespace
+	}
+	return namespace + "/" + ref.Name
+}
+
+// getHTTPRouteMatches returns the matches of the ring, a request is routed to the ring when it matches any of them
+// Every match carries the path of the ring along with one of its groups or matchers
+func getHTTPRouteMatches(routing *ringsv1alpha1.RingRouting) []gatewayv1.HTTPRouteMatch {
+	newMatch := func() gatewayv1.HTTPRouteMatch {
+		path := getRingPath(routing)
+		if path == "" {
+			path = "/"
+		}
+		prefix := gatewayv1.PathMatchPathPrefix
+		return gatewayv1.HTTPRouteMatch{
+			Path: &gatewayv1.HTTPPathMatch{Type: &prefix, Value: &path},
+		}
+	}
+
+	if isProductionRing(routing) {
+		return []gatewayv1.HTTPRouteMatch{newMatch()}
+	}
+
+	groups := getRingGroups(routing)
+	if len(groups) == 0 && len(routing.Matchers) == 0 {
+		groups = []ringsv1alpha1.RingGroup{routing.Group}
+	}
+
+	matches := []gatewayv1.HTTPRouteMatch{}
+	for _, group := range groups {
+		m := newMatch()
+		m.Headers = []gatewayv1.HTTPHeaderMatch{newHeaderMatch(gatewayv1.MatchExact, getRoutingKey(), group.Name)}
+		matches = append(matches, m)
+	}
+	for _, matcher := range routing.Matchers {
+		m := newMatch()
+		switch matcher.Type {
+		case ringsv1alpha1.MatchHeaderRegex:
+			m.Headers = []gatewayv1.HTTPHeaderMatch{newHeaderMatch(gatewayv1.MatchRegularExpression, matcher.Name, matcher.Value)}
+		case ringsv1alpha1.MatchCookie:
+			m.Headers = []gatewayv1.HTTPHeaderMatch{newHeaderMatch(gatewayv1.MatchRegularExpression, "Cookie", cookieRegexp(matcher.Name, matcher.Value))}
+		case ringsv1alpha1.MatchQuery:
+			exact := gatewayv1.MatchExact
+			m.QueryParams = []gatewayv1.HTTPQueryParamMatch{{Type: &exact, Name: matcher.Name, Value: matcher.Value}}
+		default:
+			m.Headers = []gatewayv1.HTTPHeaderMatch{newHeaderMatch(gatewayv1.MatchExact, matcher.Name, matcher.Value)}
+		}
+		matches = append(matches, m)
+	}
+	return matches
+}
+
+func newHeaderMatch(matchType gatewayv1.MatchType, name, value string) gatewayv1.HTTPHeaderMatch {
+	return gatewayv1.HTTPHeaderMatch{Type: &matchType, Name: name, Value: value}
+}
+
+// getHTTPRouteFilters returns the filters stamping the ring headers and stripping the path prefix of the ring
+func getHTTPRouteFilters(cr *ringsv1alpha1.Ring) []gatewayv1.HTTPRouteFilter {
+	routing := &cr.Spec.Routing
+	filters := []gatewayv1.HTTPRouteFilter{}
+
+	if isStampingHeaders(routing) {
+		values := getStampHeaderValues(cr)
+		names := []string{}
+		for name := range values {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		headers := make([]gatewayv1.HTTPHeader, len(names))
+		for i, name := range names {
+			headers[i] = gatewayv1.HTTPHeader{Name: name, Value: values[name]}
+		}
+		filters = append(filters,
+			gatewayv1.HTTPRouteFilter{
+				Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+				RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: headers},
+			},
+			gatewayv1.HTTPRouteFilter{
+				Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+				ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: headers},
+			},
+		)
+	}
+
+	// There is nothing to strip from rings routed on every path
+	if getPathRewrite(routing) == ringsv1alpha1.RewriteStrip && getRingPath(routing) != "" {
+		root := "/"
+		filters = append(filters, gatewayv1.HTTPRouteFilter{
+			Type: gatewayv1.HTTPRouteFilterURLRewrite,
+			URLRewrite: &gatewayv1.HTTPURLRewriteFilter{
+				Path: &gatewayv1.HTTPPathModifier{
+					Type:               gatewayv1.PrefixMatchHTTPPathModifier,
+					ReplacePrefixMatch: &root,
+				},
+			},
+		})
+	}
+	return filters
+}
+
+// getHTTPBackendRefs returns the Services of the ring, weighted across the branches of its split
+// Every port of the ring is exposed for every branch so the ratio between branches is kept, and every
+// branch stamps its own header as the rule only stamps the headers shared by the branches
+func getHTTPBackendRefs(cr *ringsv1alpha1.Ring) []gatewayv1.HTTPBackendRef {
+	routing := cr.Spec.Routing
+	refs := []gatewayv1.HTTPBackendRef{}
+	if len(routing.Split) == 0 {
+		for _, port := range routing.Ports {
+			p := port.Port
+			refs = append(refs, gatewayv1.HTTPBackendRef{Name: cr.Name, Port: &p})
+		}
+		return refs
+	}
+
+	for _, b := range routing.Split {
+		weight := int32(b.Weight)
+		var filters []gatewayv1.HTTPRouteFilter
+		if isStampingHeaders(&routing) {
+			headers := []gatewayv1.HTTPHeader{{Name: ringBranchHeader, Value: b.Branch}}
+			filters = []gatewayv1.HTTPRouteFilter{
+				{
+					Type:                  gatewayv1.HTTPRouteFilterRequestHeaderModifier,
+					RequestHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: headers},
+				},
+				{
+					Type:                   gatewayv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gatewayv1.HTTPHeaderFilter{Set: headers},
+				},
+			}
+		}
+		for _, port := range routing.Ports {
+			p := port.Port
+			refs = append(refs, gatewayv1.HTTPBackendRef{
+				Name:    getSplitServiceName(cr, b.Branch),
+				Port:    &p,
+				Weight:  &weight,
+				Filters: filters,
+			})
+		}
+	}
+	return refs
+}
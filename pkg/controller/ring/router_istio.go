@@ -0,0 +1,414 @@
+package ring
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	istio "github.com/microsoft/ring-operator/pkg/istio/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const istioRouterName = "istio"
+
+// istioRouter routes the rings of a service with a VirtualService bound to the Istio Gateway set in
+// RING_ISTIO_GATEWAY, every ring of the service contributes an http route to it
+// The branches of the service are the subsets of a DestinationRule, on a Service selecting every branch
+type istioRouter struct {
+	gateway string
+}
+
+func newIstioRouter() (Router, error) {
+	gateway := strings.TrimSpace(os.Getenv("RING_ISTIO_GATEWAY"))
+	if gateway == "" {
+		return nil, errors.New("the istio router requires RING_ISTIO_GATEWAY, the Gateway the VirtualServices are bound to as name or namespace/name")
+	}
+	return &istioRouter{gateway: gateway}, nil
+}
+
+func (i *istioRouter) Name() string {
+	return istioRouterName
+}
+
+func (i *istioRouter) AddToScheme(s *runtime.Scheme) error {
+	return istio.AddToScheme(s)
+}
+
+// OwnedTypes returns the shared Istio objects, the Service of the subsets is not watched as the Services
+// controlled by the rings are
+func (i *istioRouter) OwnedTypes() []OwnedType {
+	return []OwnedType{
+		{Object: &istio.VirtualService{}, List: &istio.VirtualServiceList{}, Shared: true},
+		{Object: &istio.DestinationRule{}, List: &istio.DestinationRuleList{}, Shared: true},
+	}
+}
+
+// Validate rejects the parts of the ring implemented with Traefik Middlewares and options, which have no
+// VirtualService equivalent, the rings whose Service would collide with the Service of the subsets and the
+// rings with several ports, as their destinations select a single port
+func (i *istioRouter) Validate(cr *ringsv1alpha1.Ring) field.ErrorList {
+	errs := field.ErrorList{}
+	routing := &cr.Spec.Routing
+	path := field.NewPath("spec", "routing")
+
+	if cr.Name == routing.Service {
+		errs = append(errs, field.Invalid(field.NewPath("metadata", "name"), cr.Name, "the istio router names the Service of the subsets after the service of the ring"))
+	}
+	if len(routing.Ports) > 1 {
+		errs = append(errs, field.Invalid(path.Child("ports"), len(routing.Ports), "the route of a ring sends its requests to a single port with the istio router"))
+	}
+	for i, m := range routing.Matchers {
+		if m.Type == ringsv1alpha1.MatchClientIP {
+			errs = append(errs, field.NotSupported(path.Child("matchers").Index(i).Child("type"), m.Type,
+				[]string{string(ringsv1alpha1.MatchHeader), string(ringsv1alpha1.MatchHeaderRegex), string(ringsv1alpha1.MatchCookie), string(ringsv1alpha1.MatchQuery)}))
+		}
+	}
+	if getPathRewrite(routing) == ringsv1alpha1.RewriteReplace {
+		errs = append(errs, field.NotSupported(path.Child("path", "rewrite"), routing.Path.Rewrite,
+			[]string{string(ringsv1alpha1.RewriteStrip), string(ringsv1alpha1.RewriteKeep)}))
+	}
+	if routing.RateLimit != nil {
+		errs = append(errs, field.Forbidden(path.Child("rateLimit"), "rate limits are not supported by the istio router"))
+	}
+	if len(routing.Middlewares) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("middlewares"), "middlewares are not supported by the istio router"))
+	}
+	if len(routing.EntryPoints) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("entryPoints"), "the rings of a service share the Gateway set in RING_ISTIO_GATEWAY"))
+	}
+	if tls := routing.TLS; tls != nil {
+		// TLS is terminated by the servers of the Gateway, which may reference the Secret of the ring
+		if tls.CertResolver != "" || tls.Options != nil || len(tls.Domains) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("tls"), "only secretName and certificate are supported by the istio router"))
+		}
+	}
+	return errs
+}
+
+// Route returns no object owned by the ring alone, its route is part of the VirtualService of its service
+func (i *istioRouter) Route(cr *ringsv1alpha1.Ring) (*Routing, error) {
+	return &Routing{}, nil
+}
+
+// SharesRouting reports whether both rings route the same service of a namespace
+func (i *istioRouter) SharesRouting(a, b *ringsv1alpha1.Ring) bool {
+	return a.Namespace == b.Namespace && a.Spec.Routing.Service == b.Spec.Routing.Service
+}
+
+// RouteShared returns the VirtualService, DestinationRule and Service of the subsets of the service of the ring
+func (i *istioRouter) RouteShared(cr *ringsv1alpha1.Ring, rings []*ringsv1alpha1.Ring) ([]runtime.Object, error) {
+	service := cr.Spec.Routing.Service
+	objMeta := metav1.ObjectMeta{
+		Name:      service,
+		Namespace: cr.Namespace,
+		Labels:    map[string]string{"service": service},
+	}
+
+	rings = sortIstioRoutes(rings)
+	routes := make([]istio.HTTPRoute, len(rings))
+	for n, ring := range rings {
+		routes[n] = newIstioRoute(ring)
+	}
+
+	vs := &istio.VirtualService{
+		ObjectMeta: *objMeta.DeepCopy(),
+		Spec: istio.VirtualServiceSpec{
+			Hosts:    getIstioHosts(rings),
+			Gateways: []string{i.gateway},
+			HTTP:     routes,
+		},
+	}
+	dr := &istio.DestinationRule{
+		ObjectMeta: *objMeta.DeepCopy(),
+		Spec: istio.DestinationRuleSpec{
+			Host:    service,
+			Subsets: getIstioSubsets(rings),
+		},
+	}
+	svc := &corev1.Service{
+		ObjectMeta: *objMeta.DeepCopy(),
+		Spec: corev1.ServiceSpec{
+			Ports:    getIstioServicePorts(rings),
+			Selector: map[string]string{"service": service},
+		},
+	}
+	return []runtime.Object{vs, dr, svc}, nil
+}
+
+// sortIstioRoutes orders the rings the way their routes are evaluated by Istio: the first route matching
+// a request wins, so the production rings come last as catch-all routes and longer paths come first
+func sortIstioRoutes(rings []*ringsv1alpha1.Ring) []*ringsv1alpha1.Ring {
+	sorted := append([]*ringsv1alpha1.Ring{}, rings...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		ra, rb := &sorted[a].Spec.Routing, &sorted[b].Spec.Routing
+		if isProductionRing(ra) != isProductionRing(rb) {
+			return isProductionRing(rb)
+		}
+		if len(getRingPath(ra)) != len(getRingPath(rb)) {
+			return len(getRingPath(ra)) > len(getRingPath(rb))
+		}
+		return sorted[a].Name < sorted[b].Name
+	})
+	return sorted
+}
+
+// newIstioRoute returns the http route of the ring in the VirtualService of its service
+func newIstioRoute(cr *ringsv1alpha1.Ring) istio.HTTPRoute {
+	routing := &cr.Spec.Routing
+	route := istio.HTTPRoute{
+		Name:  cr.Name,
+		Match: getIstioMatches(routing),
+		Route: getIstioDestinations(cr),
+	}
+
+	if isStripping(routing) {
+		route.Rewrite = &istio.HTTPRewrite{URI: "/"}
+	}
+	if isStampingHeaders(routing) {
+		values := getStampHeaderValues(cr)
+		route.Headers = &istio.Headers{
+			Request:  &istio.HeaderOperations{Set: values},
+			Response: &istio.HeaderOperations{Set: values},
+		}
+	}
+	return route
+}
+
+// isStripping reports whether the path of the ring is removed from the requests, there is nothing to strip
+// from rings routed on every path
+func isStripping(routing *ringsv1alpha1.RingRouting) bool {
+	return getPathRewrite(routing) == ringsv1alpha1.RewriteStrip && getRingPath(routing) != ""
+}
+
+// getIstioMatches returns the matches of the ring, a request is routed to the ring when it matches any of them
+// Every match carries the path and one of the hosts of the ring along with one of its groups or matchers
+func getIstioMatches(routing *ringsv1alpha1.RingRouting) []istio.HTTPMatchRequest {
+	// Istio rewrites the matched prefix, the path is matched with its trailing slash when it is stripped so
+	// that /path/x is forwarded as /x rather than //x
+	uris := []*istio.StringMatch{{Prefix: "/"}}
+	if path := getRingPath(routing); isStripping(routing) {
+		uris = []*istio.StringMatch{{Exact: path}, {Prefix: path + "/"}}
+	} else if path != "" {
+		uris = []*istio.StringMatch{{Prefix: path}}
+	}
+
+	authorities := []*istio.StringMatch{nil}
+	if len(routing.Hosts) > 0 {
+		authorities = make([]*istio.StringMatch, len(routing.Hosts))
+		for n, host := range routing.Hosts {
+			authorities[n] = getIstioAuthority(host)
+		}
+	}
+
+	base := []istio.HTTPMatchRequest{}
+	for _, authority := range authorities {
+		for _, uri := range uris {
+			base = append(base, istio.HTTPMatchRequest{URI: uri, Authority: authority})
+		}
+	}
+	if isProductionRing(routing) {
+		return base
+	}
+
+	groups := getRingGroups(routing)
+	if len(groups) == 0 && len(routing.Matchers) == 0 {
+		groups = []ringsv1alpha1.RingGroup{routing.Group}
+	}
+
+	conditions := []func(*istio.HTTPMatchRequest){}
+	for _, group := range groups {
+		value := group.Name
+		conditions = append(conditions, func(m *istio.HTTPMatchRequest) {
+			m.Headers = map[string]istio.StringMatch{strings.ToLower(getRoutingKey()): {Exact: value}}
+		})
+	}
+	for _, matcher := range routing.Matchers {
+		matcher := matcher
+		conditions = append(conditions, func(m *istio.HTTPMatchRequest) {
+			name := strings.ToLower(matcher.Name)
+			switch matcher.Type {
+			case ringsv1alpha1.MatchHeaderRegex:
+				m.Headers = map[string]istio.StringMatch{name: {Regex: matcher.Value}}
+			case ringsv1alpha1.MatchCookie:
+				// Istio regular expressions match the whole header
+				m.Headers = map[string]istio.StringMatch{"cookie": {Regex: ".*" + cookieRegexp(matcher.Name, matcher.Value) + ".*"}}
+			case ringsv1alpha1.MatchQuery:
+				m.QueryParams = map[string]istio.StringMatch{matcher.Name: {Exact: matcher.Value}}
+			default:
+				m.Headers = map[string]istio.StringMatch{name: {Exact: matcher.Value}}
+			}
+		})
+	}
+
+	matches := []istio.HTTPMatchRequest{}
+	for _, condition := range conditions {
+		for _, b := range base {
+			m := b
+			condition(&m)
+			matches = append(matches, m)
+		}
+	}
+	return matches
+}
+
+// getIstioAuthority matches the authority of the requests for a host of the ring, a wildcard host matches a
+// single label
+func getIstioAuthority(host string) *istio.StringMatch {
+	if strings.HasPrefix(host, "*.") {
+		return &istio.StringMatch{Regex: `[^.]+` + regexp.QuoteMeta(host[1:]) + `(:[0-9]+)?`}
+	}
+	return &istio.StringMatch{Exact: host}
+}
+
+// getIstioHosts returns the hosts of the rings of the VirtualService, every host when one of the rings
+// is routed on every host
+func getIstioHosts(rings []*ringsv1alpha1.Ring) []string {
+	seen := map[string]bool{}
+	hosts := []string{}
+	for _, ring := range rings {
+		if len(ring.Spec.Routing.Hosts) == 0 {
+			return []string{"*"}
+		}
+		for _, host := range normalizeHosts(ring.Spec.Routing.Hosts) {
+			if !seen[host] {
+				seen[host] = true
+				hosts = append(hosts, host)
+			}
+		}
+	}
+	if len(hosts) == 0 {
+		return []string{"*"}
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+// getIstioSubsetName returns the name of the subset of a branch, the subsets of every version of a service
+// share the DestinationRule
+func getIstioSubsetName(routing *ringsv1alpha1.RingRouting, branch string) string {
+	return routing.Version + "-" + branch
+}
+
+// getIstioDestinations returns the subsets of the ring, weighted across the branches of its split
+func getIstioDestinations(cr *ringsv1alpha1.Ring) []istio.HTTPRouteDestination {
+	routing := &cr.Spec.Routing
+	newDestination := func(branch string) istio.HTTPRouteDestination {
+		d := istio.HTTPRouteDestination{Destination: istio.Destination{
+			Host:   routing.Service,
+			Subset: getIstioSubsetName(routing, branch),
+		}}
+		if len(routing.Ports) > 0 {
+			d.Destination.Port = &istio.PortSelector{Number: uint32(routing.Ports[0].Port)}
+		}
+		return d
+	}
+
+	if len(routing.Split) == 0 {
+		return []istio.HTTPRouteDestination{newDestination(routing.Branch)}
+	}
+
+	// The route stamps the headers shared by the branches, every destination stamps its own branch
+	weights := getIstioWeights(routing.Split)
+	destinations := make([]istio.HTTPRouteDestination, len(routing.Split))
+	for n, b := range routing.Split {
+		destinations[n] = newDestination(b.Branch)
+		destinations[n].Weight = weights[n]
+		if isStampingHeaders(routing) {
+			values := map[string]string{ringBranchHeader: b.Branch}
+			destinations[n].Headers = &istio.Headers{
+				Request:  &istio.HeaderOperations{Set: values},
+				Response: &istio.HeaderOperations{Set: values},
+			}
+		}
+	}
+	return destinations
+}
+
+// getIstioWeights scales the weights of the split to the 100 Istio expects, the rounding remainder goes
+// to the heaviest branch
+func getIstioWeights(split []ringsv1alpha1.RingBranchWeight) []int32 {
+	total, heaviest := 0, 0
+	for n, b := range split {
+		total += b.Weight
+		if b.Weight > split[heaviest].Weight {
+			heaviest = n
+		}
+	}
+
+	weights := make([]int32, len(split))
+	if total == 0 {
+		weights[0] = 100
+		return weights
+	}
+	sum := int32(0)
+	for n, b := range split {
+		weights[n] = int32(b.Weight * 100 / total)
+		sum += weights[n]
+	}
+	weights[heaviest] += 100 - sum
+	return weights
+}
+
+// getIstioSubsets returns a subset for every branch routed by the rings, selected by version and branch
+func getIstioSubsets(rings []*ringsv1alpha1.Ring) []istio.Subset {
+	seen := map[string]bool{}
+	subsets := []istio.Subset{}
+	for _, ring := range rings {
+		routing := &ring.Spec.Routing
+		for _, branch := range getRingBranches(routing) {
+			name := getIstioSubsetName(routing, branch)
+			if seen[name] {
+				continue
+			}
+			seen[name] = true
+			subsets = append(subsets, istio.Subset{Name: name, Labels: getServiceSelector(routing, branch)})
+		}
+	}
+	sort.Slice(subsets, func(a, b int) bool { return subsets[a].Name < subsets[b].Name })
+	return subsets
+}
+
+// getIstioServicePorts returns the ports of every ring of the Service of the subsets, by port number
+// A number takes the port of the first ring by name exposing it, and a name already taken by a lower number is
+// suffixed with the number as the API server rejects Services with duplicate port names
+func getIstioServicePorts(rings []*ringsv1alpha1.Ring) []corev1.ServicePort {
+	sorted := append([]*ringsv1alpha1.Ring{}, rings...)
+	sort.Slice(sorted, func(a, b int) bool { return sorted[a].Name < sorted[b].Name })
+
+	seen := map[int32]bool{}
+	ports := []corev1.ServicePort{}
+	for _, ring := range sorted {
+		for _, port := range getServicePorts(&ring.Spec.Routing) {
+			if !seen[port.Port] {
+				seen[port.Port] = true
+				ports = append(ports, port)
+			}
+		}
+	}
+	sort.Slice(ports, func(a, b int) bool { return ports[a].Port < ports[b].Port })
+
+	names := map[string]bool{}
+	for i := range ports {
+		base, name := ports[i].Name, ports[i].Name
+		if base == "" {
+			base = "port"
+		}
+		for suffix := 0; names[name] || (name == "" && len(ports) > 1); suffix++ {
+			name = fmt.Sprintf("%s-%d", base, ports[i].Port)
+			if suffix > 0 {
+				name = fmt.Sprintf("%s-%d", name, suffix)
+			}
+		}
+		names[name] = true
+		ports[i].Name = name
+	}
+	return ports
+}
@@ -0,0 +1,274 @@
+package ring
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	networkingv1 "github.com/microsoft/ring-operator/pkg/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const (
+	nginxRouterName = "nginx"
+	// nginxDefaultClass is the Ingress class of the operators which don't set RING_INGRESS_CLASS
+	nginxDefaultClass = "nginx"
+	// nginxWeightedIngressName is the canary Ingress sending the weight of the other branch of a production split
+	nginxWeightedIngressName = "%s-weighted"
+	nginxAnnotationPrefix    = "nginx.ingress.kubernetes.io/"
+)
+
+// nginxRouter routes the rings with ingress-nginx Ingresses, the production ring is the primary Ingress of its
+// host and path and the other rings are canary Ingresses
+// ingress-nginx routes a single canary Ingress per host and path, which inherits the primary Ingress annotations
+type nginxRouter struct {
+	class string
+}
+
+func newNginxRouter() (Router, error) {
+	class := strings.TrimSpace(os.Getenv("RING_INGRESS_CLASS"))
+	if class == "" {
+		class = nginxDefaultClass
+	}
+	return &nginxRouter{class: class}, nil
+}
+
+func (n *nginxRouter) Name() string {
+	return nginxRouterName
+}
+
+func (n *nginxRouter) AddToScheme(s *runtime.Scheme) error {
+	return networkingv1.AddToScheme(s)
+}
+
+func (n *nginxRouter) OwnedTypes() []OwnedType {
+	return []OwnedType{
+		{Object: &networkingv1.Ingress{}, List: &networkingv1.IngressList{}},
+	}
+}
+
+// Validate rejects the rings a canary Ingress can't express: a canary matches a single header or cookie and
+// only the production ring may be weighted, between two branches
+// The backend of an Ingress is a single port, so the rings with several ports are rejected as well
+func (n *nginxRouter) Validate(cr *ringsv1alpha1.Ring) field.ErrorList {
+	errs := field.ErrorList{}
+	routing := &cr.Spec.Routing
+	path := field.NewPath("spec", "routing")
+
+	if isProductionRing(routing) {
+		if len(routing.Split) > 2 {
+			errs = append(errs, field.Invalid(path.Child("split"), len(routing.Split), "ingress-nginx weighs the production ring between two branches"))
+		}
+	} else {
+		if len(routing.Split) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("split"), "only the production ring can be weighted with ingress-nginx"))
+		}
+		if len(routing.Matchers) > 1 || (len(routing.Matchers) == 1 && len(getRingGroups(routing)) > 0) {
+			errs = append(errs, field.Forbidden(path.Child("matchers"), "ingress-nginx canaries match either the groups of the ring or a single matcher"))
+		}
+	}
+
+	if len(routing.Ports) > 1 {
+		errs = append(errs, field.Invalid(path.Child("ports"), len(routing.Ports), "the Ingress of a ring has a single backend port"))
+	}
+
+	for i, m := range routing.Matchers {
+		switch m.Type {
+		case ringsv1alpha1.MatchClientIP, ringsv1alpha1.MatchQuery:
+			errs = append(errs, field.NotSupported(path.Child("matchers").Index(i).Child("type"), m.Type,
+				[]string{string(ringsv1alpha1.MatchHeader), string(ringsv1alpha1.MatchHeaderRegex), string(ringsv1alpha1.MatchCookie)}))
+		case ringsv1alpha1.MatchCookie:
+			if m.Value != "always" {
+				errs = append(errs, field.Invalid(path.Child("matchers").Index(i).Child("value"), m.Value, "ingress-nginx routes the requests whose cookie is set to always"))
+			}
+		}
+	}
+	if getPathRewrite(routing) == ringsv1alpha1.RewriteReplace {
+		errs = append(errs, field.NotSupported(path.Child("path", "rewrite"), routing.Path.Rewrite,
+			[]string{string(ringsv1alpha1.RewriteStrip), string(ringsv1alpha1.RewriteKeep)}))
+	}
+	if routing.RateLimit != nil {
+		errs = append(errs, field.Forbidden(path.Child("rateLimit"), "rate limits are not supported by the nginx router"))
+	}
+	if len(routing.Middlewares) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("middlewares"), "middlewares are not supported by the nginx router"))
+	}
+	if len(routing.EntryPoints) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("entryPoints"), "the Ingresses of the rings use the class set in RING_INGRESS_CLASS"))
+	}
+	if isStampingHeaders(routing) {
+		errs = append(errs, field.Forbidden(path.Child("stampHeaders"), "ring headers are not supported by the nginx router"))
+	}
+	if tls := routing.TLS; tls != nil {
+		if tls.CertResolver != "" || tls.Options != nil || len(tls.Domains) > 0 {
+			errs = append(errs, field.Forbidden(path.Child("tls"), "only secretName and certificate are supported by the nginx router"))
+		}
+	}
+	return errs
+}
+
+// LimitRoute reports the rings ingress-nginx can't route together on the same host and path: a single canary
+// Ingress is routed per host and path, and canary Ingresses inherit the path rewrite of the primary Ingress
+func (n *nginxRouter) LimitRoute(cr, other *ringsv1alpha1.Ring, otherFirst bool) string {
+	a, b := &cr.Spec.Routing, &other.Spec.Routing
+	if getRingPath(a) != getRingPath(b) {
+		return ""
+	}
+	if (len(a.Hosts) > 0 || len(b.Hosts) > 0) && !hostsOverlap(normalizeHosts(a.Hosts), normalizeHosts(b.Hosts)) {
+		return ""
+	}
+
+	if otherFirst && isNginxCanary(a) && isNginxCanary(b) {
+		return "ingress-nginx routes a single canary Ingress per host and path"
+	}
+	if !isProductionRing(a) && isProductionRing(b) && getPathRewrite(a) != getPathRewrite(b) {
+		return fmt.Sprintf("canary Ingresses inherit the %s path rewrite of the production ring", getPathRewrite(b))
+	}
+	return ""
+}
+
+// isNginxCanary reports whether the ring takes the canary Ingress of its host and path, the production ring
+// does when it is weighted
+func isNginxCanary(routing *ringsv1alpha1.RingRouting) bool {
+	return !isProductionRing(routing) || len(routing.Split) > 1
+}
+
+// Route returns the Ingress of the ring, along with the canary Ingress of the other branch of a weighted
+// production ring
+func (n *nginxRouter) Route(cr *ringsv1alpha1.Ring) (*Routing, error) {
+	routing := &cr.Spec.Routing
+	result := &Routing{}
+
+	if !isProductionRing(routing) {
+		result.Route = n.newIngressForCR(cr, cr.Name, routing.Branch, getCanaryAnnotations(routing))
+		return result, nil
+	}
+
+	primary := routing.Branch
+	if len(routing.Split) > 0 {
+		primary = routing.Split[0].Branch
+		for _, b := range routing.Split {
+			if b.Branch == routing.Branch {
+				primary = b.Branch
+			}
+		}
+	}
+	result.Route = n.newIngressForCR(cr, cr.Name, primary, map[string]string{})
+
+	if len(routing.Split) > 1 {
+		total := 0
+		for _, b := range routing.Split {
+			total += b.Weight
+		}
+		for _, b := range routing.Split {
+			if b.Branch == primary {
+				continue
+			}
+			annotations := map[string]string{
+				nginxAnnotationPrefix + "canary":              "true",
+				nginxAnnotationPrefix + "canary-weight":       strconv.Itoa(b.Weight),
+				nginxAnnotationPrefix + "canary-weight-total": strconv.Itoa(total),
+			}
+			result.Objects = append(result.Objects, n.newIngressForCR(cr, fmt.Sprintf(nginxWeightedIngressName, cr.Name), b.Branch, annotations))
+		}
+	}
+	return result, nil
+}
+
+// newIngressForCR creates an Ingress (not yet created) sending the requests of the ring to the Service of a branch
+// The path prefix of the ring is stripped with a rewrite target, ingress-nginx only rewrites regular expression paths
+func (n *nginxRouter) newIngressForCR(cr *ringsv1alpha1.Ring, name, branch string, annotations map[string]string) *networkingv1.Ingress {
+	routing := &cr.Spec.Routing
+
+	path := getRingPath(routing)
+	pathType := networkingv1.PathTypePrefix
+	if path == "" {
+		path = "/"
+	}
+	if getPathRewrite(routing) == ringsv1alpha1.RewriteStrip && getRingPath(routing) != "" {
+		path = regexp.QuoteMeta(getRingPath(routing)) + "(/|$)(.*)"
+		pathType = networkingv1.PathTypeImplementationSpecific
+		annotations[nginxAnnotationPrefix+"use-regex"] = "true"
+		annotations[nginxAnnotationPrefix+"rewrite-target"] = "/$2"
+	}
+
+	backend := networkingv1.IngressBackend{Service: &networkingv1.IngressServiceBackend{Name: getSplitServiceName(cr, branch)}}
+	if len(routing.Ports) > 0 {
+		backend.Service.Port.Number = routing.Ports[0].Port
+	}
+	value := networkingv1.IngressRuleValue{HTTP: &networkingv1.HTTPIngressRuleValue{
+		Paths: []networkingv1.HTTPIngressPath{{Path: path, PathType: &pathType, Backend: backend}},
+	}}
+
+	rules := []networkingv1.IngressRule{{IngressRuleValue: value}}
+	if len(routing.Hosts) > 0 {
+		rules = make([]networkingv1.IngressRule, len(routing.Hosts))
+		for i, host := range routing.Hosts {
+			rules[i] = networkingv1.IngressRule{Host: host, IngressRuleValue: *value.DeepCopy()}
+		}
+	}
+
+	var tls []networkingv1.IngressTLS
+	if routing.TLS != nil {
+		tls = []networkingv1.IngressTLS{{Hosts: routing.Hosts, SecretName: getTLSSecretName(cr)}}
+	}
+
+	class := n.class
+	return &networkingv1.Ingress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        name,
+			Namespace:   cr.Namespace,
+			Labels:      cr.ObjectMeta.Labels,
+			Annotations: annotations,
+		},
+		Spec: networkingv1.IngressSpec{
+			IngressClassName: &class,
+			TLS:              tls,
+			Rules:            rules,
+		},
+	}
+}
+
+// getCanaryAnnotations returns the annotations routing the requests matching the groups, or the matcher, of
+// the ring to its canary Ingress
+func getCanaryAnnotations(routing *ringsv1alpha1.RingRouting) map[string]string {
+	annotations := map[string]string{nginxAnnotationPrefix + "canary": "true"}
+
+	if len(routing.Matchers) > 0 {
+		m := routing.Matchers[0]
+		switch m.Type {
+		case ringsv1alpha1.MatchCookie:
+			annotations[nginxAnnotationPrefix+"canary-by-cookie"] = m.Name
+		case ringsv1alpha1.MatchHeaderRegex:
+			annotations[nginxAnnotationPrefix+"canary-by-header"] = m.Name
+			annotations[nginxAnnotationPrefix+"canary-by-header-pattern"] = m.Value
+		default:
+			annotations[nginxAnnotationPrefix+"canary-by-header"] = m.Name
+			annotations[nginxAnnotationPrefix+"canary-by-header-value"] = m.Value
+		}
+		return annotations
+	}
+
+	groups := getRingGroups(routing)
+	if len(groups) == 0 {
+		groups = []ringsv1alpha1.RingGroup{routing.Group}
+	}
+	annotations[nginxAnnotationPrefix+"canary-by-header"] = getRoutingKey()
+	if len(groups) == 1 {
+		annotations[nginxAnnotationPrefix+"canary-by-header-value"] = groups[0].Name
+		return annotations
+	}
+
+	names := make([]string, len(groups))
+	for i, g := range groups {
+		names[i] = regexp.QuoteMeta(g.Name)
+	}
+	annotations[nginxAnnotationPrefix+"canary-by-header-pattern"] = "^(" + strings.Join(names, "|") + ")$"
+	return annotations
+}
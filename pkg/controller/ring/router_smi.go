@@ -0,0 +1,200 @@
+package ring
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	specs "github.com/microsoft/ring-operator/pkg/smi/specs/v1alpha4"
+	split "github.com/microsoft/ring-operator/pkg/smi/split/v1alpha4"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+const smiRouterName = "smi"
+
+// smiRouter routes the requests sent inside a service mesh to the Service of the production ring of a version,
+// the root Service, with SMI TrafficSplits
+// The production ring splits the root Service across its branches, every other ring splits the requests matching
+// its HTTPRouteGroup across its own branches
+type smiRouter struct{}
+
+func newSMIRouter() (Router, error) {
+	return &smiRouter{}, nil
+}
+
+func (m *smiRouter) Name() string {
+	return smiRouterName
+}
+
+func (m *smiRouter) AddToScheme(s *runtime.Scheme) error {
+	if err := split.AddToScheme(s); err != nil {
+		return err
+	}
+	return specs.AddToScheme(s)
+}
+
+// OwnedTypes returns the SMI objects, shared by the rings of a version as they all split the same root Service
+func (m *smiRouter) OwnedTypes() []OwnedType {
+	return []OwnedType{
+		{Object: &split.TrafficSplit{}, List: &split.TrafficSplitList{}, Shared: true},
+		{Object: &specs.HTTPRouteGroup{}, List: &specs.HTTPRouteGroupList{}, Shared: true},
+	}
+}
+
+// Validate rejects the parts of the ring a mesh can't route, the Traefik Middlewares and options and the
+// matchers on anything but headers
+// The hosts, path and TLS of the ring route the requests entering the cluster and are ignored
+func (m *smiRouter) Validate(cr *ringsv1alpha1.Ring) field.ErrorList {
+	errs := field.ErrorList{}
+	routing := &cr.Spec.Routing
+	path := field.NewPath("spec", "routing")
+
+	for i, matcher := range routing.Matchers {
+		if matcher.Type == ringsv1alpha1.MatchClientIP || matcher.Type == ringsv1alpha1.MatchQuery {
+			errs = append(errs, field.NotSupported(path.Child("matchers").Index(i).Child("type"), matcher.Type,
+				[]string{string(ringsv1alpha1.MatchHeader), string(ringsv1alpha1.MatchHeaderRegex), string(ringsv1alpha1.MatchCookie)}))
+		}
+	}
+	if routing.RateLimit != nil {
+		errs = append(errs, field.Forbidden(path.Child("rateLimit"), "rate limits are not supported by the smi router"))
+	}
+	if len(routing.Middlewares) > 0 {
+		errs = append(errs, field.Forbidden(path.Child("middlewares"), "middlewares are not supported by the smi router"))
+	}
+	if isStampingHeaders(routing) {
+		errs = append(errs, field.Forbidden(path.Child("stampHeaders"), "ring headers are not supported by the smi router"))
+	}
+	return errs
+}
+
+// Route returns no object owned by the ring alone, its TrafficSplit is rendered along the root Service
+func (m *smiRouter) Route(cr *ringsv1alpha1.Ring) (*Routing, error) {
+	return &Routing{}, nil
+}
+
+// SharesRouting reports whether both rings split the same root Service, the Service of the production ring of
+// their service and version
+func (m *smiRouter) SharesRouting(a, b *ringsv1alpha1.Ring) bool {
+	return a.Namespace == b.Namespace && a.Spec.Routing.Service == b.Spec.Routing.Service &&
+		a.Spec.Routing.Version == b.Spec.Routing.Version
+}
+
+// RouteShared returns the TrafficSplits and HTTPRouteGroups of the rings, the ones of the ring first
+// The rings are only split once a production ring provides their root Service, the ring waits for it until then
+func (m *smiRouter) RouteShared(cr *ringsv1alpha1.Ring, rings []*ringsv1alpha1.Ring) ([]runtime.Object, error) {
+	sorted := append([]*ringsv1alpha1.Ring{}, rings...)
+	sort.SliceStable(sorted, func(a, b int) bool {
+		if (sorted[a].Name == cr.Name) != (sorted[b].Name == cr.Name) {
+			return sorted[a].Name == cr.Name
+		}
+		return sorted[a].Name < sorted[b].Name
+	})
+
+	var root *ringsv1alpha1.Ring
+	for _, ring := range sorted {
+		if isProductionRing(&ring.Spec.Routing) && (root == nil || ring.Name < root.Name) {
+			root = ring
+		}
+	}
+	if root == nil {
+		for _, ring := range sorted {
+			if ring.Name == cr.Name {
+				routing := &cr.Spec.Routing
+				return nil, &routeWaitingError{
+					reason:  "WaitingForProductionRing",
+					message: fmt.Sprintf("no production ring of service %s version %s is routed to provide the root Service of the ring", routing.Service, routing.Version),
+				}
+			}
+		}
+		return nil, nil
+	}
+
+	objs := []runtime.Object{}
+	for _, ring := range sorted {
+		ts := newTrafficSplitForCR(ring, root.Name)
+		objs = append(objs, ts)
+		if !isProductionRing(&ring.Spec.Routing) {
+			objs = append(objs, newHTTPRouteGroupForCR(ring))
+		}
+	}
+	return objs, nil
+}
+
+// newTrafficSplitForCR creates the TrafficSplit (not yet created) sending the requests of the ring sent to the
+// root Service to the Services of its branches
+func newTrafficSplitForCR(cr *ringsv1alpha1.Ring, root string) *split.TrafficSplit {
+	routing := &cr.Spec.Routing
+	backends := []split.TrafficSplitBackend{{Service: cr.Name, Weight: 100}}
+	if len(routing.Split) > 0 {
+		backends = make([]split.TrafficSplitBackend, len(routing.Split))
+		for i, b := range routing.Split {
+			backends[i] = split.TrafficSplitBackend{Service: getSplitServiceName(cr, b.Branch), Weight: b.Weight}
+		}
+	}
+
+	ts := &split.TrafficSplit{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels:    cr.ObjectMeta.Labels,
+		},
+		Spec: split.TrafficSplitSpec{
+			Service:  root,
+			Backends: backends,
+		},
+	}
+	if !isProductionRing(routing) {
+		group := specs.SchemeGroupVersion.Group
+		ts.Spec.Matches = []corev1.TypedLocalObjectReference{{APIGroup: &group, Kind: "HTTPRouteGroup", Name: cr.Name}}
+	}
+	return ts
+}
+
+// newHTTPRouteGroupForCR creates the HTTPRouteGroup (not yet created) matching the requests of the groups and
+// matchers of the ring, SMI matches headers with regular expressions
+func newHTTPRouteGroupForCR(cr *ringsv1alpha1.Ring) *specs.HTTPRouteGroup {
+	routing := &cr.Spec.Routing
+	exact := func(value string) string {
+		return "^" + regexp.QuoteMeta(value) + "$"
+	}
+
+	groups := getRingGroups(routing)
+	if len(groups) == 0 && len(routing.Matchers) == 0 {
+		groups = []ringsv1alpha1.RingGroup{routing.Group}
+	}
+
+	matches := []specs.HTTPMatch{}
+	for _, group := range groups {
+		matches = append(matches, specs.HTTPMatch{
+			Name:    "group-" + group.Name,
+			Headers: map[string]string{strings.ToLower(getRoutingKey()): exact(group.Name)},
+		})
+	}
+	for i, matcher := range routing.Matchers {
+		match := specs.HTTPMatch{Name: fmt.Sprintf("matcher-%d", i)}
+		switch matcher.Type {
+		case ringsv1alpha1.MatchHeaderRegex:
+			match.Headers = map[string]string{strings.ToLower(matcher.Name): matcher.Value}
+		case ringsv1alpha1.MatchCookie:
+			match.Headers = map[string]string{"cookie": cookieRegexp(matcher.Name, matcher.Value)}
+		default:
+			match.Headers = map[string]string{strings.ToLower(matcher.Name): exact(matcher.Value)}
+		}
+		matches = append(matches, match)
+	}
+
+	return &specs.HTTPRouteGroup{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels:    cr.ObjectMeta.Labels,
+		},
+		Spec: specs.HTTPRouteGroupSpec{Matches: matches},
+	}
+}
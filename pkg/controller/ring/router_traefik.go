@@ -0,0 +1,255 @@
+package ring
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+
+	traefik "github.com/microsoft/ring-operator/pkg/traefik/v1alpha1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/client-go/discovery"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	traefikRouterName = "traefik"
+	// traefikV3Resource is only served by Traefik v3, its rules are written in the v3 syntax
+	traefikV3Resource = "serverstransporttcps"
+)
+
+// traefikRouter routes the rings with Traefik IngressRoutes, Middlewares and weighted TraefikServices
+// The objects are produced in traefik.io unless the cluster only serves the legacy traefik.containo.us group
+type traefikRouter struct {
+	groupVersion schema.GroupVersion
+	// legacyServed is set when the cluster serves the legacy group, the objects of the rings are migrated from it
+	legacyServed bool
+	// ruleSyntax is the syntax of the rules of the Traefik release serving the group
+	ruleSyntax ruleSyntax
+}
+
+func newTraefikRouter() (Router, error) {
+	return &traefikRouter{groupVersion: traefik.SchemeGroupVersion}, nil
+}
+
+func (t *traefikRouter) Name() string {
+	return traefikRouterName
+}
+
+func (t *traefikRouter) AddToScheme(s *runtime.Scheme) error {
+	return traefik.AddToSchemeForGroupVersion(s, t.groupVersion)
+}
+
+// DetectAPIs selects the Traefik group served by the cluster, traefik.io when it serves both groups or none
+// of them as the CRDs may not be installed yet
+// The rules are written in the v3 syntax when traefik.io serves the resources only found in Traefik v3
+func (t *traefikRouter) DetectAPIs(d discovery.DiscoveryInterface) error {
+	groups, err := d.ServerGroups()
+	if err != nil {
+		return err
+	}
+
+	served := map[string]bool{}
+	for _, g := range groups.Groups {
+		served[g.Name] = true
+	}
+	t.legacyServed = served[traefik.LegacyGroupName]
+	if t.legacyServed && !served[traefik.GroupName] {
+		t.groupVersion = traefik.LegacySchemeGroupVersion
+	}
+
+	syntax := "v2"
+	if served[traefik.GroupName] {
+		resources, err := d.ServerResourcesForGroupVersion(traefik.SchemeGroupVersion.String())
+		if err != nil {
+			return err
+		}
+		for _, r := range resources.APIResources {
+			if r.Name == traefikV3Resource {
+				t.ruleSyntax, syntax = ruleSyntaxV3, "v3"
+			}
+		}
+	}
+	log.Info("Routing rings with the Traefik API group served by the cluster", "Group", t.groupVersion.Group, "RuleSyntax", syntax)
+	return nil
+}
+
+// MigrateLegacy moves the Traefik objects controlled by rings in the namespace from the legacy group to traefik.io
+// Every object is created in traefik.io before the legacy objects are deleted, so the rings stay routed while
+// Traefik serves both groups
+func (t *traefikRouter) MigrateLegacy(c client.Client, namespace string) error {
+	if !t.legacyServed || t.groupVersion != traefik.SchemeGroupVersion {
+		return nil
+	}
+
+	legacy := []*unstructured.Unstructured{}
+	for _, kind := range traefik.Kinds {
+		list := &unstructured.UnstructuredList{}
+		list.SetGroupVersionKind(traefik.LegacySchemeGroupVersion.WithKind(kind + "List"))
+		if err := c.List(context.TODO(), client.InNamespace(namespace), list); err != nil {
+			return err
+		}
+
+		for i := range list.Items {
+			item := &list.Items[i]
+			owner := metav1.GetControllerOf(item)
+			if owner == nil || owner.Kind != "Ring" || !strings.HasPrefix(owner.APIVersion, ringsv1alpha1.SchemeGroupVersion.Group+"/") {
+				continue
+			}
+
+			log.Info("Migrating Traefik object", "Kind", kind, "Namespace", item.GetNamespace(), "Name", item.GetName(), "Group", t.groupVersion.Group)
+			migrated, err := newMigratedObject(item, t.groupVersion)
+			if err != nil {
+				return err
+			}
+			if err := c.Create(context.TODO(), migrated); err != nil && !apierrors.IsAlreadyExists(err) {
+				return err
+			}
+			legacy = append(legacy, item)
+		}
+	}
+
+	// IngressRoutes are deleted first so they never refer to a deleted object
+	for i := len(legacy) - 1; i >= 0; i-- {
+		log.Info("Deleting legacy Traefik object", "Kind", legacy[i].GetKind(), "Namespace", legacy[i].GetNamespace(), "Name", legacy[i].GetName())
+		if err := c.Delete(context.TODO(), legacy[i]); err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// newMigratedObject copies the object to the given group version, without the metadata set by the API server
+func newMigratedObject(obj *unstructured.Unstructured, gv schema.GroupVersion) (*unstructured.Unstructured, error) {
+	migrated := &unstructured.Unstructured{Object: map[string]interface{}{}}
+	migrated.SetGroupVersionKind(gv.WithKind(obj.GetKind()))
+	migrated.SetName(obj.GetName())
+	migrated.SetNamespace(obj.GetNamespace())
+	migrated.SetLabels(obj.GetLabels())
+	migrated.SetAnnotations(obj.GetAnnotations())
+	migrated.SetOwnerReferences(obj.GetOwnerReferences())
+
+	spec, found, err := unstructured.NestedFieldCopy(obj.Object, "spec")
+	if err != nil {
+		return nil, err
+	}
+	if found {
+		migrated.Object["spec"] = spec
+	}
+	return migrated, nil
+}
+
+func (t *traefikRouter) OwnedTypes() []OwnedType {
+	return []OwnedType{
+		{Object: &traefik.Middleware{}, List: &traefik.MiddlewareList{}},
+		{Object: &traefik.TraefikService{}, List: &traefik.TraefikServiceList{}},
+		{Object: &traefik.IngressRoute{}, List: &traefik.IngressRouteList{}},
+	}
+}
+
+// Validate accepts every ring, the ring API is modelled on Traefik, but the ipWhiteList middlewares
+// Traefik v3 no longer serves
+func (t *traefikRouter) Validate(cr *ringsv1alpha1.Ring) field.ErrorList {
+	errs := field.ErrorList{}
+	if t.ruleSyntax != ruleSyntaxV3 {
+		return errs
+	}
+
+	path := field.NewPath("spec", "routing", "middlewares")
+	for i, m := range cr.Spec.Routing.Middlewares {
+		if m.Spec == nil {
+			continue
+		}
+		// The specs which can't be read are reported by the validation of the middlewares
+		if spec, err := decodeMiddlewareSpec(m.Spec); err == nil && spec.IPWhiteList != nil {
+			errs = append(errs, field.Forbidden(path.Index(i).Child("spec", "ipWhiteList"), "ipWhiteList middlewares are not served by Traefik v3"))
+		}
+	}
+	return errs
+}
+
+// Route returns the IngressRoute of the ring along with the Middlewares it owns and the weighted
+// TraefikService of its split
+func (t *traefikRouter) Route(cr *ringsv1alpha1.Ring) (*Routing, error) {
+	middlewares, err := getRingMiddlewares(cr)
+	if err != nil {
+		return nil, err
+	}
+
+	routing := &Routing{}
+	for _, m := range middlewares {
+		if m.ref {
+			continue
+		}
+		routing.Objects = append(routing.Objects, newMiddlewareForCR(cr, m))
+		routing.MiddlewareNames = append(routing.MiddlewareNames, m.name)
+	}
+
+	if len(cr.Spec.Routing.Split) > 0 {
+		routing.Objects = append(routing.Objects, newTraefikServiceForCR(cr))
+	}
+
+	ing := newIngressRouteForCR(cr, t.ruleSyntax, middlewares)
+	routing.Route = ing
+	routing.Match = ing.Spec.Routes[0].Match
+	return routing, nil
+}
+
+// newIngressRouteForCR creates the Traefik IngressRoute object (not yet created) routing the requests of the ring
+// to its Services with a rule in the given syntax, through its middlewares
+func newIngressRouteForCR(cr *ringsv1alpha1.Ring, syntax ruleSyntax, middlewares []ringMiddleware) *traefik.IngressRoute {
+	routing := cr.Spec.Routing
+
+	return &traefik.IngressRoute{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      cr.Name,
+			Namespace: cr.Namespace,
+			Labels:    cr.ObjectMeta.Labels,
+		},
+		Spec: traefik.IngressRouteSpec{
+			EntryPoints: getEntryPoints(&routing),
+			TLS:         getIngressRouteTLS(cr),
+			Routes: []traefik.Route{
+				{
+					Match:       createRule(&routing).render(syntax),
+					Kind:        "Rule",
+					Services:    getTraefikServices(cr),
+					Middlewares: getMiddlewareRefs(cr, middlewares),
+				},
+			},
+		},
+	}
+}
+
+// newMiddlewareForCR creates a new Traefik Middleware object (not yet created) owned by the ring
+func newMiddlewareForCR(cr *ringsv1alpha1.Ring, m ringMiddleware) *traefik.Middleware {
+	return &traefik.Middleware{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      m.name,
+			Namespace: cr.Namespace,
+			Labels:    cr.ObjectMeta.Labels,
+		},
+		Spec: m.spec,
+	}
+}
+
+// newTraefikServiceForCR creates a new weighted TraefikService object (not yet created) spreading
+// the traffic of the ring across the Services of the split
+func newTraefikServiceForCR(cr *ringsv1alpha1.Ring) *traefik.TraefikService {
+	return &traefik.TraefikService{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf(weightedServiceName, cr.Name),
+			Namespace: cr.Namespace,
+			Labels:    cr.ObjectMeta.Labels,
+		},
+		Spec: traefik.TraefikServiceSpec{
+			Weighted: &traefik.WeightedRoundRobin{Services: getWeightedServices(cr)},
+		},
+	}
+}
@@ -0,0 +1,160 @@
+package ring
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// ruleSyntax is the syntax of the Traefik router rules
+// Traefik v3 renamed the header matchers, matches a query parameter on its name and value and takes a single
+// value per matcher, its HostRegexp matches a plain regular expression
+type ruleSyntax int
+
+const (
+	ruleSyntaxV2 ruleSyntax = iota
+	ruleSyntaxV3
+)
+
+// rule is a Traefik router rule, rendered with String in the v2 syntax
+type rule interface {
+	String() string
+	render(syntax ruleSyntax) string
+}
+
+// matcher is a single Traefik matcher such as PathPrefix(`/hello`)
+type matcher struct {
+	name string
+	args []string
+	// v3 is the matcher in the v3 syntax, when it differs
+	v3 rule
+}
+
+// and is satisfied when every one of its rules is
+type and []rule
+
+// or is satisfied when any one of its rules is
+type or []rule
+
+func pathPrefix(prefix string) rule {
+	return matcher{name: "PathPrefix", args: []string{prefix}}
+}
+
+func host(hosts ...string) rule {
+	return matcher{name: "Host", args: hosts, v3: singleValues("Host", hosts)}
+}
+
+// hostRegexp matches the wildcard hosts (eg: *.example.com) on any single subdomain
+func hostRegexp(wildcards ...string) rule {
+	v2, v3 := make([]string, len(wildcards)), make([]string, len(wildcards))
+	for i, h := range wildcards {
+		domain := strings.TrimPrefix(h, "*")
+		v2[i] = "{subdomain:[a-zA-Z0-9-]+}" + domain
+		v3[i] = "^[a-zA-Z0-9-]+" + regexp.QuoteMeta(domain) + "$"
+	}
+	return matcher{name: "HostRegexp", args: v2, v3: singleValues("HostRegexp", v3)}
+}
+
+func headers(key, value string) rule {
+	return matcher{name: "Headers", args: []string{key, value}, v3: matcher{name: "Header", args: []string{key, value}}}
+}
+
+func headersRegexp(key, expr string) rule {
+	return matcher{name: "HeadersRegexp", args: []string{key, expr}, v3: matcher{name: "HeaderRegexp", args: []string{key, expr}}}
+}
+
+func query(key, value string) rule {
+	return matcher{name: "Query", args: []string{key + "=" + value}, v3: matcher{name: "Query", args: []string{key, value}}}
+}
+
+func clientIP(ranges ...string) rule {
+	return matcher{name: "ClientIP", args: ranges, v3: singleValues("ClientIP", ranges)}
+}
+
+// singleValues returns the v3 rule matching any of the values, with a matcher per value
+func singleValues(name string, values []string) rule {
+	if len(values) == 1 {
+		return matcher{name: name, args: values}
+	}
+	rules := make(or, len(values))
+	for i, v := range values {
+		rules[i] = matcher{name: name, args: []string{v}}
+	}
+	return rules
+}
+
+// cookie matches a cookie by name and value, Traefik has no cookie matcher so the Cookie header is matched instead
+func cookie(name, value string) rule {
+	return headersRegexp("Cookie", cookieRegexp(name, value))
+}
+
+// cookieRegexp returns the expression matching the Cookie header of the requests carrying the cookie
+func cookieRegexp(name, value string) string {
+	return `(^|;\s*)` + regexp.QuoteMeta(name) + `=` + regexp.QuoteMeta(value) + `(;|$)`
+}
+
+func (m matcher) String() string {
+	return m.render(ruleSyntaxV2)
+}
+
+func (m matcher) render(syntax ruleSyntax) string {
+	if syntax == ruleSyntaxV3 && m.v3 != nil {
+		return m.v3.render(syntax)
+	}
+	args := make([]string, len(m.args))
+	for i, arg := range m.args {
+		args[i] = quoteRuleArg(arg)
+	}
+	return m.name + "(" + strings.Join(args, ", ") + ")"
+}
+
+func (a and) String() string {
+	return a.render(ruleSyntaxV2)
+}
+
+func (a and) render(syntax ruleSyntax) string {
+	return join(a, " && ", syntax, func(r rule) bool {
+		o, ok := r.(or)
+		return ok && len(o) > 1
+	})
+}
+
+func (o or) String() string {
+	return o.render(ruleSyntaxV2)
+}
+
+func (o or) render(syntax ruleSyntax) string {
+	return join(o, " || ", syntax, func(r rule) bool {
+		a, ok := r.(and)
+		return ok && len(a) > 1
+	})
+}
+
+// join renders the rules with the operator, wrapping the rules of lower precedence in parentheses
+func join(rules []rule, op string, syntax ruleSyntax, wrap func(rule) bool) string {
+	parts := make([]string, len(rules))
+	for i, r := range rules {
+		parts[i] = r.render(syntax)
+		if wrap(resolveRule(r, syntax)) {
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, op)
+}
+
+// resolveRule returns the rule rendered in the syntax, a matcher is rendered as its v3 rule in the v3 syntax
+func resolveRule(r rule, syntax ruleSyntax) rule {
+	if m, ok := r.(matcher); ok && syntax == ruleSyntaxV3 && m.v3 != nil {
+		return resolveRule(m.v3, syntax)
+	}
+	return r
+}
+
+// quoteRuleArg quotes a matcher argument with backticks, which need no escaping, unless the argument
+// itself contains a backtick
+func quoteRuleArg(arg string) string {
+	if strings.Contains(arg, "`") {
+		return strconv.Quote(arg)
+	}
+	return "`" + arg + "`"
+}
@@ -3,144 +3,219 @@ package ring
 import (
 	"fmt"
 	"os"
+	"strings"
 	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
 
-	traefikcfg "github.com/containous/traefik/pkg/config"
-	traefik "github.com/containous/traefik/pkg/provider/kubernetes/crd/traefik/v1alpha1"
+	traefik "github.com/microsoft/ring-operator/pkg/traefik/v1alpha1"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	ratelimitMiddlewareName   = "%s-ratelimit"
+	stampMiddlewareName       = "%s-stamp"
 	stripPrefixMiddlewareName = "%s-stripprefix"
+	replacePathMiddlewareName = "%s-replacepath"
+	userMiddlewareName        = "%s-%s"
+	weightedServiceName       = "%s-weighted"
+	splitServiceName          = "%s-%s"
+
+	// defaultPathTemplate is the path prefix of the rings which don't set one
+	defaultPathTemplate = "/{service}/{version}"
 )
 
-// createIngressRoute will create the Traefik IngressRoute resource to handle routing from external to the service
-func (r *ReconcileRing) newIngressRouteForCR(cr *ringsv1alpha1.Ring) *traefik.IngressRoute {
-	r.logger.Info("Creating Ingress Route", "IngressRoute.Namespace", cr.Namespace, "IngressRoute.Name", cr.Name)
+// getEntryPoints returns the Traefik entrypoints the ring is exposed on
+// Rings which don't set any use the operator default from RING_ENTRYPOINTS (eg: "web,websecure"),
+// or http, https and internal when it is not set
+func getEntryPoints(routing *ringsv1alpha1.RingRouting) []string {
+	if len(routing.EntryPoints) > 0 {
+		return routing.EntryPoints
+	}
+
+	entryPoints := []string{}
+	for _, e := range strings.Split(os.Getenv("RING_ENTRYPOINTS"), ",") {
+		if e = strings.TrimSpace(e); e != "" {
+			entryPoints = append(entryPoints, e)
+		}
+	}
+	if len(entryPoints) > 0 {
+		return entryPoints
+	}
 
-	// Use entrypoints set for Traefik
-	entryPoints := []string{
+	return []string{
 		"http",
 		"https",
 		"internal",
 	}
+}
 
-	// Create match rule from routing descriptor
-	routing := cr.Spec.Routing
-	match := createMatchRule(&routing)
-
-	// Get service ports
-	serviceName := fmt.Sprintf("%s-%s-%s", routing.Service, routing.Version, routing.Branch)
-	ports := getTraefikServices(serviceName, &routing)
-
-	middlewareRefs := []traefik.MiddlewareRef{
-		// createRateLimitMiddlewareRef(serviceName, cr.Namespace),
-		createStripPrefixMiddlewareRef(serviceName, cr.Namespace),
+// getTraefikServices returns a mapping from the ring port definition into the Traefik service definition
+// as required for the IngressRoute
+// A ring splitting its traffic across branches is served by its weighted TraefikService instead
+func getTraefikServices(cr *ringsv1alpha1.Ring) []traefik.Service {
+	if len(cr.Spec.Routing.Split) > 0 {
+		return []traefik.Service{
+			{
+				Name: fmt.Sprintf(weightedServiceName, cr.Name),
+				Kind: "TraefikService",
+			},
+		}
 	}
 
-	objMeta := metav1.ObjectMeta{
-		Name:      cr.Name,
-		Namespace: cr.Namespace,
-		Labels:    cr.ObjectMeta.Labels,
+	ports := cr.Spec.Routing.Ports
+	tPorts := make([]traefik.Service, len(ports))
+	for i, port := range ports {
+		tPorts[i] = traefik.Service{
+			Name: cr.Name,
+			Port: port.Port,
+		}
 	}
+	return tPorts
+}
 
-	return &traefik.IngressRoute{
-		ObjectMeta: objMeta,
-		Spec: traefik.IngressRouteSpec{
-			EntryPoints: entryPoints,
-			Routes: []traefik.Route{
-				{
-					Match:       match,
-					Kind:        "Rule",
-					Services:    ports,
-					Middlewares: middlewareRefs,
-				},
-			},
-		},
+// getRingGroups returns the target groups of the ring
+// The single group of older rings comes first, followed by the groups list without duplicates
+func getRingGroups(routing *ringsv1alpha1.RingRouting) []ringsv1alpha1.RingGroup {
+	groups := []ringsv1alpha1.RingGroup{}
+	seen := map[string]bool{}
+	for _, group := range append([]ringsv1alpha1.RingGroup{routing.Group}, routing.Groups...) {
+		if group.Name == "" || seen[group.Name] {
+			continue
+		}
+		seen[group.Name] = true
+		groups = append(groups, group)
 	}
+	return groups
 }
 
-func (r *ReconcileRing) updateIngressRouteForCR(ing *traefik.IngressRoute, cr *ringsv1alpha1.Ring) *traefik.IngressRoute {
-	r.logger.Info("Updating Ingress Route", "IngressRoute.Namespace", cr.Namespace, "IngressRoute.Name", cr.Name)
+// isProductionRing returns whether the ring targets every user rather than specific groups
+func isProductionRing(routing *ringsv1alpha1.RingRouting) bool {
+	for _, group := range getRingGroups(routing) {
+		if group.Name == "*" {
+			return true
+		}
+	}
+	return false
+}
 
-	newIng := ing.DeepCopy()
+// getRingPath returns the path prefix the ring is routed on, or an empty path when path routing is disabled
+func getRingPath(routing *ringsv1alpha1.RingRouting) string {
+	template := defaultPathTemplate
+	if path := routing.Path; path != nil {
+		if path.Disabled {
+			return ""
+		}
+		if path.Template != "" {
+			template = path.Template
+		}
+	}
 
-	// Create match rule from routing descriptor
-	routing := cr.Spec.Routing
-	match := createMatchRule(&routing)
+	return strings.NewReplacer(
+		"{service}", routing.Service,
+		"{version}", routing.Version,
+		"{branch}", routing.Branch,
+	).Replace(template)
+}
 
-	// Get service ports
-	ports := getTraefikServices(cr.Name, &routing)
+// getPathRewrite returns how the request path is rewritten before it reaches the ring Service
+func getPathRewrite(routing *ringsv1alpha1.RingRouting) ringsv1alpha1.RingPathRewrite {
+	if routing.Path == nil || routing.Path.Rewrite == "" {
+		return ringsv1alpha1.RewriteStrip
+	}
+	return routing.Path.Rewrite
+}
 
-	middlewareRefs := []traefik.MiddlewareRef{
-		// createRateLimitMiddlewareRef(serviceName, cr.Namespace),
-		createStripPrefixMiddlewareRef(cr.Name, cr.Namespace),
+// createHostRule returns the rule matching any of the ring hosts, or nil when the ring has none
+// Wildcard hosts (eg: *.example.com) are matched on any single subdomain
+func createHostRule(hosts []string) rule {
+	plain, wildcards := []string{}, []string{}
+	for _, h := range hosts {
+		if strings.HasPrefix(h, "*.") {
+			wildcards = append(wildcards, h)
+		} else {
+			plain = append(plain, h)
+		}
 	}
 
-	newIng.Labels = cr.ObjectMeta.Labels
-	newIng.Spec.Routes = []traefik.Route{
-		{
-			Match:       match,
-			Kind:        "Rule",
-			Services:    ports,
-			Middlewares: middlewareRefs,
-		},
+	rules := or{}
+	if len(plain) > 0 {
+		rules = append(rules, host(plain...))
+	}
+	if len(wildcards) > 0 {
+		rules = append(rules, hostRegexp(wildcards...))
 	}
-	return newIng
-}
 
-// getTraefikServices returns a mapping from the ring port definition into the Traefik service definition
-// as required for the IngressRoute
-func getTraefikServices(serviceName string, routing *ringsv1alpha1.RingRouting) []traefik.Service {
-	ports := routing.Ports
-	tPorts := make([]traefik.Service, len(ports))
-	for i, port := range ports {
-		tPorts[i] = traefik.Service{
-			Name: serviceName,
-			Port: port.Port,
-		}
+	switch len(rules) {
+	case 0:
+		return nil
+	case 1:
+		return rules[0]
+	default:
+		return rules
 	}
-	return tPorts
 }
 
-// createMatchRule will generate a routing rule for the ring
+// createRule returns the routing rule of the ring
 // it handles special cases such as production ring
-func createMatchRule(routing *ringsv1alpha1.RingRouting) string {
+func createRule(routing *ringsv1alpha1.RingRouting) rule {
+	match := and{}
+	if hosts := createHostRule(routing.Hosts); hosts != nil {
+		match = append(match, hosts)
+	}
+	if path := getRingPath(routing); path != "" {
+		match = append(match, pathPrefix(path))
+	}
+
 	// Handle production
-	if routing.Group.Name == "*" {
-		return fmt.Sprintf("PathPrefix(`/%s/%s`)", routing.Service, routing.Version)
+	if isProductionRing(routing) {
+		if len(match) == 0 {
+			return pathPrefix("/")
+		}
+		return match
 	}
 
-	var (
-		routingKey     = "group"
-		ringRoutingKey = ""
-	)
+	routingKey := getRoutingKey()
+	groups := getRingGroups(routing)
+	if len(groups) == 0 && len(routing.Matchers) == 0 {
+		groups = []ringsv1alpha1.RingGroup{routing.Group}
+	}
 
-	ringRoutingKey = os.Getenv("RING_ROUTING_KEY")
-	if ringRoutingKey != "" {
-		routingKey = ringRoutingKey
+	// A request is routed to the ring when it matches any of the groups or matchers
+	members := or{}
+	for _, group := range groups {
+		members = append(members, headers(routingKey, group.Name))
+	}
+	for _, m := range routing.Matchers {
+		members = append(members, createMatcherRule(m))
 	}
 
-	return fmt.Sprintf("PathPrefix(`/%s/%s`) && Headers(`%s`, `%s`)", routing.Service, routing.Version, routingKey, routing.Group.Name)
+	if len(match) == 0 {
+		return members
+	}
+	return append(match, members)
 }
 
-// createStripPrefixMiddlewareRef returns a middleware reference to the stripPrefix
-// middleware associated with this ring
-func createStripPrefixMiddlewareRef(name, namespace string) traefik.MiddlewareRef {
-	return traefik.MiddlewareRef{
-		Name:      fmt.Sprintf(stripPrefixMiddlewareName, name),
-		Namespace: namespace,
+// getRoutingKey returns the header carrying the group of a request, RING_ROUTING_KEY or group when it is not set
+func getRoutingKey() string {
+	if routingKey := os.Getenv("RING_ROUTING_KEY"); routingKey != "" {
+		return routingKey
 	}
+	return "group"
 }
 
-// createRateLimitMiddlewareRef returns a middleware reference to the rateLimit
-// middleware associated with this ring
-func createRateLimitMiddlewareRef(name, namespace string) traefik.MiddlewareRef {
-	return traefik.MiddlewareRef{
-		Name:      fmt.Sprintf(ratelimitMiddlewareName, name),
-		Namespace: namespace,
+// createMatcherRule returns the Traefik rule of a ring matcher
+func createMatcherRule(m ringsv1alpha1.RingMatcher) rule {
+	switch m.Type {
+	case ringsv1alpha1.MatchHeaderRegex:
+		return headersRegexp(m.Name, m.Value)
+	case ringsv1alpha1.MatchCookie:
+		return cookie(m.Name, m.Value)
+	case ringsv1alpha1.MatchQuery:
+		return query(m.Name, m.Value)
+	case ringsv1alpha1.MatchClientIP:
+		return clientIP(m.Value)
+	default:
+		return headers(m.Name, m.Value)
 	}
 }
 
@@ -158,20 +233,25 @@ func getServicePorts(routing *ringsv1alpha1.RingRouting) []corev1.ServicePort {
 	return ports
 }
 
-// createService will create the service for accessing the target Pods by name
-func (r *ReconcileRing) newServiceForCR(cr *ringsv1alpha1.Ring) *corev1.Service {
-	r.logger.Info("Creating Service", "Service.Namespace", cr.Namespace, "Service.Name", cr.Name)
-
-	routing := cr.Spec.Routing
-	selector := map[string]string{
+// getServiceSelector returns the selector matching the deployments of the given branch of the ring service
+func getServiceSelector(routing *ringsv1alpha1.RingRouting, branch string) map[string]string {
+	return map[string]string{
 		"service": routing.Service,
 		"version": routing.Version,
-		"branch":  routing.Branch,
+		"branch":  branch,
 	}
+}
+
+// createService will create the service for accessing the target Pods of the branch by name
+func (r *ReconcileRing) newServiceForCR(cr *ringsv1alpha1.Ring, name, branch string) *corev1.Service {
+	r.logger.Info("Creating Service", "Service.Namespace", cr.Namespace, "Service.Name", name)
+
+	routing := cr.Spec.Routing
+	selector := getServiceSelector(&routing, branch)
 
 	ports := getServicePorts(&routing)
 	objMeta := metav1.ObjectMeta{
-		Name:      cr.Name,
+		Name:      name,
 		Namespace: cr.Namespace,
 		Labels:    cr.ObjectMeta.Labels,
 	}
@@ -185,16 +265,12 @@ func (r *ReconcileRing) newServiceForCR(cr *ringsv1alpha1.Ring) *corev1.Service
 	}
 }
 
-func (r *ReconcileRing) updateServiceForCR(svc *corev1.Service, cr *ringsv1alpha1.Ring) *corev1.Service {
-	r.logger.Info("Updating Service", "Service.Namespace", cr.Namespace, "Service.Name", cr.Name)
+func (r *ReconcileRing) updateServiceForCR(svc *corev1.Service, cr *ringsv1alpha1.Ring, branch string) *corev1.Service {
+	r.logger.Info("Updating Service", "Service.Namespace", svc.Namespace, "Service.Name", svc.Name)
 	newSvc := svc.DeepCopy()
 
 	routing := cr.Spec.Routing
-	selector := map[string]string{
-		"service": routing.Service,
-		"version": routing.Version,
-		"branch":  routing.Branch,
-	}
+	selector := getServiceSelector(&routing, branch)
 
 	ports := getServicePorts(&routing)
 	newSvc.Labels = cr.ObjectMeta.Labels
@@ -202,37 +278,3 @@ func (r *ReconcileRing) updateServiceForCR(svc *corev1.Service, cr *ringsv1alpha
 	newSvc.Spec.Selector = selector
 	return newSvc
 }
-
-// newStripPrefixForCR creates a new Traefik Middleware object (not yet created) representing
-// a StripPrefix to remove the path from the request so that the service only sees the path it expects
-// out of the cluster (eg: /hello-world/v1/home.html -> /home.html)
-func (r *ReconcileRing) newStripPrefixForCR(cr *ringsv1alpha1.Ring) *traefik.Middleware {
-	r.logger.Info("Creating StripPrefix", "StripPrefix.Namespace", cr.Namespace, "StripPrefix.Name", cr.Name)
-
-	routing := cr.Spec.Routing
-	path := fmt.Sprintf("/%s/%s", routing.Service, routing.Version)
-	objMeta := metav1.ObjectMeta{
-		Name:      fmt.Sprintf("%s-stripprefix", cr.Name),
-		Namespace: cr.Namespace,
-		Labels:    cr.ObjectMeta.Labels,
-	}
-
-	return &traefik.Middleware{
-		ObjectMeta: objMeta,
-		Spec: traefikcfg.Middleware{
-			StripPrefix: &traefikcfg.StripPrefix{Prefixes: []string{path}},
-		},
-	}
-}
-
-func (r *ReconcileRing) updateStripPrefixForCR(sp *traefik.Middleware, cr *ringsv1alpha1.Ring) *traefik.Middleware {
-	r.logger.Info("Updating StripPrefix", "StripPrefix.Namespace", cr.Namespace, "StripPrefix.Name", cr.Name)
-	newSp := sp.DeepCopy()
-
-	routing := cr.Spec.Routing
-	path := fmt.Sprintf("/%s/%s", routing.Service, routing.Version)
-
-	newSp.Labels = cr.ObjectMeta.Labels
-	newSp.Spec.StripPrefix.Prefixes = []string{path}
-	return newSp
-}
@@ -0,0 +1,6 @@
+// Package v1 contains the subset of the Kubernetes Gateway API (gateway.networking.k8s.io/v1) that the
+// ring operator produces. The Gateway API module requires a far newer Kubernetes than the one pinned
+// in go.mod, so the types are kept here in the shape served by Gateway API v1.0+.
+// +k8s:deepcopy-gen=package
+// +groupName=gateway.networking.k8s.io
+package v1
@@ -0,0 +1,184 @@
+package v1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ParentReference identifies the Gateway, and optionally its listener, a route attaches to.
+type ParentReference struct {
+	Group       *string `json:"group,omitempty"`
+	Kind        *string `json:"kind,omitempty"`
+	Namespace   *string `json:"namespace,omitempty"`
+	Name        string  `json:"name"`
+	SectionName *string `json:"sectionName,omitempty"`
+}
+
+// PathMatchType is how a path is matched.
+type PathMatchType string
+
+// Path match types.
+const (
+	PathMatchExact      PathMatchType = "Exact"
+	PathMatchPathPrefix PathMatchType = "PathPrefix"
+)
+
+// HTTPPathMatch matches the path of a request.
+type HTTPPathMatch struct {
+	Type  *PathMatchType `json:"type,omitempty"`
+	Value *string        `json:"value,omitempty"`
+}
+
+// MatchType is how a header or query parameter value is matched.
+type MatchType string
+
+// Header and query parameter match types.
+const (
+	MatchExact             MatchType = "Exact"
+	MatchRegularExpression MatchType = "RegularExpression"
+)
+
+// HTTPHeaderMatch matches a header of a request.
+type HTTPHeaderMatch struct {
+	Type  *MatchType `json:"type,omitempty"`
+	Name  string     `json:"name"`
+	Value string     `json:"value"`
+}
+
+// HTTPQueryParamMatch matches a query parameter of a request.
+type HTTPQueryParamMatch struct {
+	Type  *MatchType `json:"type,omitempty"`
+	Name  string     `json:"name"`
+	Value string     `json:"value"`
+}
+
+// HTTPRouteMatch is satisfied by the requests matching its path, every one of its headers and every
+// one of its query parameters.
+type HTTPRouteMatch struct {
+	Path        *HTTPPathMatch        `json:"path,omitempty"`
+	Headers     []HTTPHeaderMatch     `json:"headers,omitempty"`
+	QueryParams []HTTPQueryParamMatch `json:"queryParams,omitempty"`
+}
+
+// HTTPRouteFilterType is the type of an HTTPRouteFilter.
+type HTTPRouteFilterType string
+
+// Filter types.
+const (
+	HTTPRouteFilterRequestHeaderModifier  HTTPRouteFilterType = "RequestHeaderModifier"
+	HTTPRouteFilterResponseHeaderModifier HTTPRouteFilterType = "ResponseHeaderModifier"
+	HTTPRouteFilterURLRewrite             HTTPRouteFilterType = "URLRewrite"
+)
+
+// HTTPHeader is a header name and value.
+type HTTPHeader struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// HTTPHeaderFilter modifies the headers of a request or response.
+type HTTPHeaderFilter struct {
+	Set    []HTTPHeader `json:"set,omitempty"`
+	Add    []HTTPHeader `json:"add,omitempty"`
+	Remove []string     `json:"remove,omitempty"`
+}
+
+// HTTPPathModifierType is how a path is rewritten.
+type HTTPPathModifierType string
+
+// Path modifier types.
+const (
+	FullPathHTTPPathModifier    HTTPPathModifierType = "ReplaceFullPath"
+	PrefixMatchHTTPPathModifier HTTPPathModifierType = "ReplacePrefixMatch"
+)
+
+// HTTPPathModifier rewrites the path of a request.
+type HTTPPathModifier struct {
+	Type               HTTPPathModifierType `json:"type"`
+	ReplaceFullPath    *string              `json:"replaceFullPath,omitempty"`
+	ReplacePrefixMatch *string              `json:"replacePrefixMatch,omitempty"`
+}
+
+// HTTPURLRewriteFilter rewrites the hostname or path of a request before it is forwarded.
+type HTTPURLRewriteFilter struct {
+	Hostname *string           `json:"hostname,omitempty"`
+	Path     *HTTPPathModifier `json:"path,omitempty"`
+}
+
+// HTTPRouteFilter processes the requests of a rule, exactly one of its filters is set.
+type HTTPRouteFilter struct {
+	Type                   HTTPRouteFilterType   `json:"type"`
+	RequestHeaderModifier  *HTTPHeaderFilter     `json:"requestHeaderModifier,omitempty"`
+	ResponseHeaderModifier *HTTPHeaderFilter     `json:"responseHeaderModifier,omitempty"`
+	URLRewrite             *HTTPURLRewriteFilter `json:"urlRewrite,omitempty"`
+}
+
+// HTTPBackendRef is a backend the requests of a rule are sent to, by weight, through its own filters.
+type HTTPBackendRef struct {
+	Group     *string           `json:"group,omitempty"`
+	Kind      *string           `json:"kind,omitempty"`
+	Name      string            `json:"name"`
+	Namespace *string           `json:"namespace,omitempty"`
+	Port      *int32            `json:"port,omitempty"`
+	Weight    *int32            `json:"weight,omitempty"`
+	Filters   []HTTPRouteFilter `json:"filters,omitempty"`
+}
+
+// HTTPRouteRule sends the requests matching any of its matches through its filters to its backends.
+type HTTPRouteRule struct {
+	Matches     []HTTPRouteMatch  `json:"matches,omitempty"`
+	Filters     []HTTPRouteFilter `json:"filters,omitempty"`
+	BackendRefs []HTTPBackendRef  `json:"backendRefs,omitempty"`
+}
+
+// HTTPRouteSpec is a specification for an HTTPRoute resource.
+type HTTPRouteSpec struct {
+	ParentRefs []ParentReference `json:"parentRefs,omitempty"`
+	Hostnames  []string          `json:"hostnames,omitempty"`
+	Rules      []HTTPRouteRule   `json:"rules,omitempty"`
+}
+
+// RouteConditionAccepted is set by a Gateway controller once it accepted the route.
+const RouteConditionAccepted = "Accepted"
+
+// Condition is a condition reported by a Gateway controller, metav1.Condition is not part of the
+// Kubernetes version pinned in go.mod.
+type Condition struct {
+	Type               string      `json:"type"`
+	Status             string      `json:"status"`
+	ObservedGeneration int64       `json:"observedGeneration,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime"`
+	Reason             string      `json:"reason"`
+	Message            string      `json:"message"`
+}
+
+// RouteParentStatus is the status of a route as seen by the controller of one of its parents.
+type RouteParentStatus struct {
+	ParentRef      ParentReference `json:"parentRef"`
+	ControllerName string          `json:"controllerName"`
+	Conditions     []Condition     `json:"conditions,omitempty"`
+}
+
+// HTTPRouteStatus is the status of an HTTPRoute resource.
+type HTTPRouteStatus struct {
+	Parents []RouteParentStatus `json:"parents"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRoute is a Gateway API HTTPRoute CRD specification.
+type HTTPRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   HTTPRouteSpec   `json:"spec"`
+	Status HTTPRouteStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteList is a list of HTTPRoutes.
+type HTTPRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []HTTPRoute `json:"items"`
+}
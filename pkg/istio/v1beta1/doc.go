@@ -0,0 +1,6 @@
+// Package v1beta1 contains the subset of the Istio networking API (networking.istio.io/v1beta1) that
+// the ring operator produces. The Istio client module requires a far newer Kubernetes than the one
+// pinned in go.mod, so the types are kept here in the shape served by Istio 1.8+.
+// +k8s:deepcopy-gen=package
+// +groupName=networking.istio.io
+package v1beta1
@@ -0,0 +1,96 @@
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// StringMatch matches a string exactly, by prefix or by RE2 regular expression, exactly one of them is set.
+type StringMatch struct {
+	Exact  string `json:"exact,omitempty"`
+	Prefix string `json:"prefix,omitempty"`
+	Regex  string `json:"regex,omitempty"`
+}
+
+// HTTPMatchRequest is satisfied by the requests matching every one of its conditions.
+type HTTPMatchRequest struct {
+	Name        string                 `json:"name,omitempty"`
+	URI         *StringMatch           `json:"uri,omitempty"`
+	Authority   *StringMatch           `json:"authority,omitempty"`
+	Headers     map[string]StringMatch `json:"headers,omitempty"`
+	QueryParams map[string]StringMatch `json:"queryParams,omitempty"`
+}
+
+// HTTPRewrite rewrites the matched part of the URI, or the authority, of a request before it is forwarded.
+type HTTPRewrite struct {
+	URI       string `json:"uri,omitempty"`
+	Authority string `json:"authority,omitempty"`
+}
+
+// PortSelector selects a port of the destination host.
+type PortSelector struct {
+	Number uint32 `json:"number,omitempty"`
+}
+
+// Destination is a subset of a service in the service registry.
+type Destination struct {
+	Host   string        `json:"host"`
+	Subset string        `json:"subset,omitempty"`
+	Port   *PortSelector `json:"port,omitempty"`
+}
+
+// HTTPRouteDestination is a destination the requests of a route are sent to, by weight.
+// The weights of the destinations of a route add up to 100.
+type HTTPRouteDestination struct {
+	Destination Destination `json:"destination"`
+	Weight      int32       `json:"weight,omitempty"`
+	Headers     *Headers    `json:"headers,omitempty"`
+}
+
+// HeaderOperations modifies the headers of a request or response.
+type HeaderOperations struct {
+	Set    map[string]string `json:"set,omitempty"`
+	Add    map[string]string `json:"add,omitempty"`
+	Remove []string          `json:"remove,omitempty"`
+}
+
+// Headers modifies the headers of the requests and responses of a route.
+type Headers struct {
+	Request  *HeaderOperations `json:"request,omitempty"`
+	Response *HeaderOperations `json:"response,omitempty"`
+}
+
+// HTTPRoute sends the requests matching any of its matches to its destinations, the first route
+// matching a request is used.
+type HTTPRoute struct {
+	Name    string                 `json:"name,omitempty"`
+	Match   []HTTPMatchRequest     `json:"match,omitempty"`
+	Rewrite *HTTPRewrite           `json:"rewrite,omitempty"`
+	Route   []HTTPRouteDestination `json:"route,omitempty"`
+	Headers *Headers               `json:"headers,omitempty"`
+}
+
+// VirtualServiceSpec is a specification for a VirtualService resource.
+type VirtualServiceSpec struct {
+	Hosts    []string    `json:"hosts,omitempty"`
+	Gateways []string    `json:"gateways,omitempty"`
+	HTTP     []HTTPRoute `json:"http,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualService is an Istio VirtualService CRD specification.
+type VirtualService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec VirtualServiceSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// VirtualServiceList is a list of VirtualServices.
+type VirtualServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []VirtualService `json:"items"`
+}
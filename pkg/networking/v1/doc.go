@@ -0,0 +1,6 @@
+// Package v1 contains the Ingress of the Kubernetes networking API (networking.k8s.io/v1) that the ring
+// operator produces for ingress-nginx. The Kubernetes version pinned in go.mod predates it, so the types
+// are kept here in the shape served by Kubernetes 1.19+.
+// +k8s:deepcopy-gen=package
+// +groupName=networking.k8s.io
+package v1
@@ -0,0 +1,95 @@
+package v1
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// PathType is how the path of an Ingress rule is matched.
+type PathType string
+
+// Path types.
+const (
+	PathTypeExact                  PathType = "Exact"
+	PathTypePrefix                 PathType = "Prefix"
+	PathTypeImplementationSpecific PathType = "ImplementationSpecific"
+)
+
+// ServiceBackendPort is a port of a Service, by name or number.
+type ServiceBackendPort struct {
+	Name   string `json:"name,omitempty"`
+	Number int32  `json:"number,omitempty"`
+}
+
+// IngressServiceBackend is a port of a Service of the namespace of the Ingress.
+type IngressServiceBackend struct {
+	Name string             `json:"name"`
+	Port ServiceBackendPort `json:"port,omitempty"`
+}
+
+// IngressBackend is where the requests matching a path are sent.
+type IngressBackend struct {
+	Service *IngressServiceBackend `json:"service,omitempty"`
+}
+
+// HTTPIngressPath sends the requests matching its path to its backend.
+type HTTPIngressPath struct {
+	Path     string         `json:"path,omitempty"`
+	PathType *PathType      `json:"pathType"`
+	Backend  IngressBackend `json:"backend"`
+}
+
+// HTTPIngressRuleValue is the list of the paths of a rule.
+type HTTPIngressRuleValue struct {
+	Paths []HTTPIngressPath `json:"paths"`
+}
+
+// IngressRuleValue is the value of a rule, only HTTP rules exist.
+type IngressRuleValue struct {
+	HTTP *HTTPIngressRuleValue `json:"http,omitempty"`
+}
+
+// IngressRule routes the requests for a host, every host when it is empty.
+type IngressRule struct {
+	Host             string `json:"host,omitempty"`
+	IngressRuleValue `json:",inline,omitempty"`
+}
+
+// IngressTLS terminates TLS for its hosts with the certificate of a Secret.
+type IngressTLS struct {
+	Hosts      []string `json:"hosts,omitempty"`
+	SecretName string   `json:"secretName,omitempty"`
+}
+
+// IngressSpec is a specification for an Ingress resource.
+type IngressSpec struct {
+	IngressClassName *string         `json:"ingressClassName,omitempty"`
+	DefaultBackend   *IngressBackend `json:"defaultBackend,omitempty"`
+	TLS              []IngressTLS    `json:"tls,omitempty"`
+	Rules            []IngressRule   `json:"rules,omitempty"`
+}
+
+// IngressStatus is the status of an Ingress resource.
+type IngressStatus struct {
+	LoadBalancer corev1.LoadBalancerStatus `json:"loadBalancer,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Ingress is a Kubernetes networking.k8s.io/v1 Ingress.
+type Ingress struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec   IngressSpec   `json:"spec"`
+	Status IngressStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IngressList is a list of Ingresses.
+type IngressList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []Ingress `json:"items"`
+}
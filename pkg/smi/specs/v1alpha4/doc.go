@@ -0,0 +1,5 @@
+// Package v1alpha4 contains the HTTPRouteGroup of the SMI traffic specs API (specs.smi-spec.io/v1alpha4) that
+// the ring operator produces for service meshes such as Linkerd and Open Service Mesh.
+// +k8s:deepcopy-gen=package
+// +groupName=specs.smi-spec.io
+package v1alpha4
@@ -0,0 +1,38 @@
+package v1alpha4
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// HTTPMatch is satisfied by the requests matching its path, one of its methods and every one of its headers,
+// header values are regular expressions.
+type HTTPMatch struct {
+	Name      string            `json:"name"`
+	Methods   []string          `json:"methods,omitempty"`
+	PathRegex string            `json:"pathRegex,omitempty"`
+	Headers   map[string]string `json:"headers,omitempty"`
+}
+
+// HTTPRouteGroupSpec is a specification for an HTTPRouteGroup resource.
+type HTTPRouteGroupSpec struct {
+	Matches []HTTPMatch `json:"matches,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteGroup is an SMI HTTPRouteGroup CRD specification.
+type HTTPRouteGroup struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec HTTPRouteGroupSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// HTTPRouteGroupList is a list of HTTPRouteGroups.
+type HTTPRouteGroupList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []HTTPRouteGroup `json:"items"`
+}
@@ -0,0 +1,5 @@
+// Package v1alpha4 contains the TrafficSplit of the SMI traffic split API (split.smi-spec.io/v1alpha4) that
+// the ring operator produces for service meshes such as Linkerd and Open Service Mesh.
+// +k8s:deepcopy-gen=package
+// +groupName=split.smi-spec.io
+package v1alpha4
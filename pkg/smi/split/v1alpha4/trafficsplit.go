@@ -0,0 +1,39 @@
+package v1alpha4
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TrafficSplitBackend is a Service the traffic of the root Service is sent to, by weight.
+type TrafficSplitBackend struct {
+	Service string `json:"service"`
+	Weight  int    `json:"weight"`
+}
+
+// TrafficSplitSpec is a specification for a TrafficSplit resource.
+type TrafficSplitSpec struct {
+	// Service is the root Service the clients send their requests to
+	Service  string                             `json:"service"`
+	Backends []TrafficSplitBackend              `json:"backends"`
+	Matches  []corev1.TypedLocalObjectReference `json:"matches,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TrafficSplit is an SMI TrafficSplit CRD specification.
+type TrafficSplit struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec TrafficSplitSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TrafficSplitList is a list of TrafficSplits.
+type TrafficSplitList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []TrafficSplit `json:"items"`
+}
@@ -0,0 +1,7 @@
+// Package v1alpha1 contains the subset of the Traefik CRD API (traefik.io/v1alpha1) that the ring
+// operator produces. The Traefik module pinned in go.mod predates TraefikService and weighted
+// services, so the types are kept here in the shape served by Traefik v2.1+. Traefik served the
+// same types under traefik.containo.us/v1alpha1 before v2.10.
+// +k8s:deepcopy-gen=package
+// +groupName=traefik.io
+package v1alpha1
@@ -0,0 +1,76 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// IngressRouteSpec is a specification for a IngressRouteSpec resource.
+type IngressRouteSpec struct {
+	Routes      []Route  `json:"routes"`
+	EntryPoints []string `json:"entryPoints,omitempty"`
+	TLS         *TLS     `json:"tls,omitempty"`
+}
+
+// TLS contains the TLS certificates configuration of the routes.
+type TLS struct {
+	SecretName   string        `json:"secretName,omitempty"`
+	Options      *TLSOptionRef `json:"options,omitempty"`
+	CertResolver string        `json:"certResolver,omitempty"`
+	Domains      []Domain      `json:"domains,omitempty"`
+}
+
+// TLSOptionRef is a ref to the TLSOption resources.
+type TLSOptionRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// Domain holds a domain name with SANs.
+type Domain struct {
+	Main string   `json:"main,omitempty"`
+	SANs []string `json:"sans,omitempty"`
+}
+
+// Route contains the set of routes.
+type Route struct {
+	Match       string          `json:"match"`
+	Kind        string          `json:"kind"`
+	Priority    int             `json:"priority,omitempty"`
+	Services    []Service       `json:"services,omitempty"`
+	Middlewares []MiddlewareRef `json:"middlewares,omitempty"`
+}
+
+// Service defines an upstream to proxy traffic. It is either a Kubernetes Service
+// or, when Kind is TraefikService, a TraefikService in the same namespace.
+type Service struct {
+	Name      string `json:"name"`
+	Kind      string `json:"kind,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Port      int32  `json:"port,omitempty"`
+	Weight    *int   `json:"weight,omitempty"`
+}
+
+// MiddlewareRef is a ref to the Middleware resources.
+type MiddlewareRef struct {
+	Name      string `json:"name"`
+	Namespace string `json:"namespace,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IngressRoute is an Ingress CRD specification.
+type IngressRoute struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec IngressRouteSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// IngressRouteList is a list of IngressRoutes.
+type IngressRouteList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []IngressRoute `json:"items"`
+}
@@ -0,0 +1,117 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// MiddlewareSpec holds the configuration of a Middleware, only one of the fields is set.
+type MiddlewareSpec struct {
+	StripPrefix      *StripPrefix      `json:"stripPrefix,omitempty"`
+	ReplacePathRegex *ReplacePathRegex `json:"replacePathRegex,omitempty"`
+	RateLimit        *RateLimit        `json:"rateLimit,omitempty"`
+	Headers          *Headers          `json:"headers,omitempty"`
+	Compress         *Compress         `json:"compress,omitempty"`
+	Retry            *Retry            `json:"retry,omitempty"`
+	BasicAuth        *BasicAuth        `json:"basicAuth,omitempty"`
+	ForwardAuth      *ForwardAuth      `json:"forwardAuth,omitempty"`
+	IPWhiteList      *IPWhiteList      `json:"ipWhiteList,omitempty"`
+	RedirectScheme   *RedirectScheme   `json:"redirectScheme,omitempty"`
+}
+
+// StripPrefix holds the StripPrefix configuration.
+type StripPrefix struct {
+	Prefixes []string `json:"prefixes,omitempty"`
+}
+
+// ReplacePathRegex holds the ReplacePathRegex configuration.
+type ReplacePathRegex struct {
+	Regex       string `json:"regex,omitempty"`
+	Replacement string `json:"replacement,omitempty"`
+}
+
+// RateLimit holds the RateLimit configuration.
+type RateLimit struct {
+	Average         int64            `json:"average,omitempty"`
+	Period          string           `json:"period,omitempty"`
+	Burst           *int64           `json:"burst,omitempty"`
+	SourceCriterion *SourceCriterion `json:"sourceCriterion,omitempty"`
+}
+
+// SourceCriterion defines what criterion is used to group requests as originating from a common source.
+type SourceCriterion struct {
+	IPStrategy        *IPStrategy `json:"ipStrategy,omitempty"`
+	RequestHeaderName string      `json:"requestHeaderName,omitempty"`
+	RequestHost       bool        `json:"requestHost,omitempty"`
+}
+
+// IPStrategy holds the IP strategy configuration.
+type IPStrategy struct {
+	Depth       int      `json:"depth,omitempty"`
+	ExcludedIPs []string `json:"excludedIPs,omitempty"`
+}
+
+// Headers holds the custom headers configuration.
+type Headers struct {
+	CustomRequestHeaders  map[string]string `json:"customRequestHeaders,omitempty"`
+	CustomResponseHeaders map[string]string `json:"customResponseHeaders,omitempty"`
+}
+
+// Compress holds the compress configuration.
+type Compress struct {
+	ExcludedContentTypes []string `json:"excludedContentTypes,omitempty"`
+}
+
+// Retry holds the retry configuration.
+type Retry struct {
+	Attempts        int    `json:"attempts,omitempty"`
+	InitialInterval string `json:"initialInterval,omitempty"`
+}
+
+// BasicAuth holds the HTTP basic authentication configuration.
+type BasicAuth struct {
+	Secret       string `json:"secret,omitempty"`
+	Realm        string `json:"realm,omitempty"`
+	RemoveHeader bool   `json:"removeHeader,omitempty"`
+	HeaderField  string `json:"headerField,omitempty"`
+}
+
+// ForwardAuth holds the forward authentication configuration.
+type ForwardAuth struct {
+	Address             string   `json:"address,omitempty"`
+	TrustForwardHeader  bool     `json:"trustForwardHeader,omitempty"`
+	AuthResponseHeaders []string `json:"authResponseHeaders,omitempty"`
+	AuthRequestHeaders  []string `json:"authRequestHeaders,omitempty"`
+}
+
+// IPWhiteList holds the IP allow list configuration.
+type IPWhiteList struct {
+	SourceRange []string    `json:"sourceRange,omitempty"`
+	IPStrategy  *IPStrategy `json:"ipStrategy,omitempty"`
+}
+
+// RedirectScheme holds the scheme redirection configuration.
+type RedirectScheme struct {
+	Scheme    string `json:"scheme,omitempty"`
+	Port      string `json:"port,omitempty"`
+	Permanent bool   `json:"permanent,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// Middleware is a specification for a Middleware resource.
+type Middleware struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec MiddlewareSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// MiddlewareList is a list of Middleware resources.
+type MiddlewareList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []Middleware `json:"items"`
+}
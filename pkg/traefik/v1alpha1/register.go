@@ -0,0 +1,50 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// GroupName is the group name for Traefik.
+const GroupName = "traefik.io"
+
+// LegacyGroupName is the group name served by the Traefik releases before v2.10.
+const LegacyGroupName = "traefik.containo.us"
+
+var (
+	// SchemeBuilder collects the scheme builder functions.
+	SchemeBuilder = runtime.NewSchemeBuilder(addKnownTypes)
+
+	// AddToScheme applies the SchemeBuilder functions to a specified scheme.
+	AddToScheme = SchemeBuilder.AddToScheme
+)
+
+// SchemeGroupVersion is group version used to register these objects.
+var SchemeGroupVersion = schema.GroupVersion{Group: GroupName, Version: "v1alpha1"}
+
+// LegacySchemeGroupVersion is the group version of the types in the legacy group.
+var LegacySchemeGroupVersion = schema.GroupVersion{Group: LegacyGroupName, Version: "v1alpha1"}
+
+// Kinds are the kinds of the types, in the order the objects they refer to are created.
+var Kinds = []string{"Middleware", "TraefikService", "IngressRoute"}
+
+// Adds the list of known types to Scheme.
+func addKnownTypes(scheme *runtime.Scheme) error {
+	return AddToSchemeForGroupVersion(scheme, SchemeGroupVersion)
+}
+
+// AddToSchemeForGroupVersion registers the types under the given group version, a type can only be registered
+// under one of the Traefik groups of a scheme.
+func AddToSchemeForGroupVersion(scheme *runtime.Scheme, gv schema.GroupVersion) error {
+	scheme.AddKnownTypes(gv,
+		&IngressRoute{},
+		&IngressRouteList{},
+		&Middleware{},
+		&MiddlewareList{},
+		&TraefikService{},
+		&TraefikServiceList{},
+	)
+	metav1.AddToGroupVersion(scheme, gv)
+	return nil
+}
@@ -0,0 +1,35 @@
+package v1alpha1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TraefikServiceSpec holds the configuration of a TraefikService, only one of the fields is set.
+type TraefikServiceSpec struct {
+	Weighted *WeightedRoundRobin `json:"weighted,omitempty"`
+}
+
+// WeightedRoundRobin spreads the requests across the services in proportion to their weight.
+type WeightedRoundRobin struct {
+	Services []Service `json:"services,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TraefikService is a specification for a TraefikService resource.
+type TraefikService struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+
+	Spec TraefikServiceSpec `json:"spec"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// TraefikServiceList is a list of TraefikService resources.
+type TraefikServiceList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []TraefikService `json:"items"`
+}
@@ -0,0 +1,10 @@
+package webhook
+
+import (
+	server "github.com/microsoft/ring-operator/pkg/webhook/default_server"
+)
+
+func init() {
+	// AddToManagerFuncs is a list of functions to create webhook servers and add them to a manager.
+	AddToManagerFuncs = append(AddToManagerFuncs, server.Add)
+}
@@ -0,0 +1,104 @@
+package conversion
+
+import (
+	"context"
+	"encoding/base64"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+)
+
+const (
+	// CRDName is the name of the Ring CustomResourceDefinition
+	CRDName = "rings.rings.microsoft.com"
+
+	// caCertKey is the key of the CA certificate in the Secret written by the webhook server
+	caCertKey = "ca-cert.pem"
+)
+
+var crdGVK = schema.GroupVersionKind{
+	Group:   "apiextensions.k8s.io",
+	Version: "v1beta1",
+	Kind:    "CustomResourceDefinition",
+}
+
+// CABundleInjector switches the conversion of the Ring CRD to the webhook server, the CRD is installed with
+// the None strategy and v1alpha1 as its only served version so it applies whether the webhook is enabled or not
+// The other versions are served along with the conversion, the API server would store them unconverted before
+// The webhook server provisions its certificate in a Secret at startup, the injector waits for it and sets
+// its CA and the webhook Service on the CRD as the API server must trust the server to convert Rings
+type CABundleInjector struct {
+	// Client reads the Secret and updates the CRD, it must not be cached
+	Client client.Client
+	// Secret holding the certificate of the webhook server
+	Secret types.NamespacedName
+	// Service in front of the webhook server
+	Service types.NamespacedName
+}
+
+var _ manager.Runnable = &CABundleInjector{}
+
+// Start injects the CA bundle once the Secret exists and returns
+func (i *CABundleInjector) Start(stop <-chan struct{}) error {
+	return wait.PollImmediateUntil(5*time.Second, func() (bool, error) {
+		if err := i.inject(); err != nil {
+			log.Error(err, "Could not inject the CA bundle into the Ring CRD, retrying")
+			return false, nil
+		}
+		return true, nil
+	}, stop)
+}
+
+func (i *CABundleInjector) inject() error {
+	secret := &corev1.Secret{}
+	if err := i.Client.Get(context.TODO(), i.Secret, secret); err != nil {
+		if errors.IsNotFound(err) {
+			log.Info("Waiting for the webhook server certificate", "Secret.Namespace", i.Secret.Namespace, "Secret.Name", i.Secret.Name)
+		}
+		return err
+	}
+
+	crd := &unstructured.Unstructured{}
+	crd.SetGroupVersionKind(crdGVK)
+	if err := i.Client.Get(context.TODO(), types.NamespacedName{Name: CRDName}, crd); err != nil {
+		return err
+	}
+
+	conversion := map[string]interface{}{
+		"strategy": "Webhook",
+		"webhookClientConfig": map[string]interface{}{
+			"caBundle": base64.StdEncoding.EncodeToString(secret.Data[caCertKey]),
+			"service": map[string]interface{}{
+				"namespace": i.Service.Namespace,
+				"name":      i.Service.Name,
+				"path":      Path,
+			},
+		},
+	}
+	if err := unstructured.SetNestedField(crd.Object, conversion, "spec", "conversion"); err != nil {
+		return err
+	}
+
+	versions, _, err := unstructured.NestedSlice(crd.Object, "spec", "versions")
+	if err != nil {
+		return err
+	}
+	for _, v := range versions {
+		if version, ok := v.(map[string]interface{}); ok {
+			version["served"] = true
+		}
+	}
+	if err := unstructured.SetNestedSlice(crd.Object, versions, "spec", "versions"); err != nil {
+		return err
+	}
+
+	log.Info("Injecting the CA bundle into the Ring CRD", "CRD.Name", CRDName)
+	return i.Client.Update(context.TODO(), crd)
+}
@@ -0,0 +1,40 @@
+package conversion
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// The ConversionReview of apiextensions.k8s.io/v1beta1, as sent by the API server to the conversion webhook of a CRD
+
+// ConversionReview describes a conversion request and its response
+type ConversionReview struct {
+	metav1.TypeMeta `json:",inline"`
+	// Request describes the attributes for the conversion request
+	// +optional
+	Request *ConversionRequest `json:"request,omitempty"`
+	// Response describes the attributes for the conversion response
+	// +optional
+	Response *ConversionResponse `json:"response,omitempty"`
+}
+
+// ConversionRequest describes the conversion request parameters
+type ConversionRequest struct {
+	// UID is an identifier for the individual request/response
+	UID types.UID `json:"uid"`
+	// DesiredAPIVersion is the version to convert given objects to (eg: "rings.microsoft.com/v1beta1")
+	DesiredAPIVersion string `json:"desiredAPIVersion"`
+	// Objects is the list of CR objects to be converted
+	Objects []runtime.RawExtension `json:"objects"`
+}
+
+// ConversionResponse describes a conversion response
+type ConversionResponse struct {
+	// UID is an identifier for the individual request/response, copied from the request
+	UID types.UID `json:"uid"`
+	// ConvertedObjects is the list of converted version of the request objects, in the same order
+	ConvertedObjects []runtime.RawExtension `json:"convertedObjects"`
+	// Result contains the result of conversion with extra details if the conversion failed
+	Result metav1.Status `json:"result"`
+}
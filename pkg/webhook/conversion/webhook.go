@@ -0,0 +1,142 @@
+package conversion
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+	ringsv1beta1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1beta1"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+)
+
+// Path is the path the conversion webhook is served on
+const Path = "/convert"
+
+var log = logf.Log.WithName("conversion")
+
+// Webhook converts Rings between the versions served by the CRD
+// Every version is converted through the v1alpha1 hub
+type Webhook struct{}
+
+var _ http.Handler = &Webhook{}
+
+// ServeHTTP answers a ConversionReview
+func (wh *Webhook) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	review := &ConversionReview{}
+	if err := json.NewDecoder(r.Body).Decode(review); err != nil || review.Request == nil {
+		log.Error(err, "Could not decode the conversion review")
+		http.Error(w, "could not decode the conversion review", http.StatusBadRequest)
+		return
+	}
+
+	review.Response = convertReview(review.Request)
+	review.Request = nil
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(review); err != nil {
+		log.Error(err, "Could not encode the conversion review")
+	}
+}
+
+// convertReview converts every object of the request, the request fails as a whole on the first error
+func convertReview(req *ConversionRequest) *ConversionResponse {
+	res := &ConversionResponse{
+		UID:              req.UID,
+		ConvertedObjects: make([]runtime.RawExtension, len(req.Objects)),
+	}
+	for i, obj := range req.Objects {
+		raw, err := Convert(obj.Raw, req.DesiredAPIVersion)
+		if err != nil {
+			log.Error(err, "Could not convert Ring", "DesiredAPIVersion", req.DesiredAPIVersion)
+			res.ConvertedObjects = nil
+			res.Result = metav1.Status{Status: metav1.StatusFailure, Message: err.Error()}
+			return res
+		}
+		res.ConvertedObjects[i] = runtime.RawExtension{Raw: raw}
+	}
+	res.Result = metav1.Status{Status: metav1.StatusSuccess}
+	return res
+}
+
+// Convert converts the JSON of a Ring to the given API version
+func Convert(raw []byte, desiredAPIVersion string) ([]byte, error) {
+	meta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, meta); err != nil {
+		return nil, err
+	}
+	if meta.APIVersion == desiredAPIVersion {
+		return raw, nil
+	}
+
+	hub, err := ToHub(raw)
+	if err != nil {
+		return nil, err
+	}
+	obj, err := FromHub(hub, desiredAPIVersion)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(obj)
+}
+
+// ToHub decodes the JSON of a Ring of any served version into the v1alpha1 hub
+func ToHub(raw []byte) (*ringsv1alpha1.Ring, error) {
+	meta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(raw, meta); err != nil {
+		return nil, err
+	}
+
+	hub := &ringsv1alpha1.Ring{}
+	switch meta.APIVersion {
+	case ringsv1alpha1.SchemeGroupVersion.String():
+		if err := json.Unmarshal(raw, hub); err != nil {
+			return nil, err
+		}
+	case ringsv1beta1.SchemeGroupVersion.String():
+		ring := &ringsv1beta1.Ring{}
+		if err := json.Unmarshal(raw, ring); err != nil {
+			return nil, err
+		}
+		if err := ring.ConvertTo(hub); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported Ring version %s", meta.APIVersion)
+	}
+	return hub, nil
+}
+
+// FromHub converts the v1alpha1 hub Ring to the given API version
+func FromHub(hub *ringsv1alpha1.Ring, apiVersion string) (runtime.Object, error) {
+	switch apiVersion {
+	case ringsv1alpha1.SchemeGroupVersion.String():
+		return hub, nil
+	case ringsv1beta1.SchemeGroupVersion.String():
+		ring := &ringsv1beta1.Ring{}
+		if err := ring.ConvertFrom(hub); err != nil {
+			return nil, err
+		}
+		return ring, nil
+	default:
+		return nil, fmt.Errorf("unsupported Ring version %s", apiVersion)
+	}
+}
+
+// Rules returns the admission rules matching the Rings of every served version
+// Admission webhooks are called for the version of the request, which is not converted beforehand
+func Rules(ops ...admissionregistrationv1beta1.OperationType) []admissionregistrationv1beta1.RuleWithOperations {
+	return []admissionregistrationv1beta1.RuleWithOperations{
+		{
+			Operations: ops,
+			Rule: admissionregistrationv1beta1.Rule{
+				APIGroups:   []string{ringsv1alpha1.SchemeGroupVersion.Group},
+				APIVersions: []string{ringsv1alpha1.SchemeGroupVersion.Version, ringsv1beta1.SchemeGroupVersion.Version},
+				Resources:   []string{"rings"},
+			},
+		},
+	}
+}
@@ -0,0 +1,148 @@
+package conversion_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+	ringsv1beta1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1beta1"
+	"github.com/microsoft/ring-operator/pkg/webhook/conversion"
+
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func review(t *testing.T, desiredAPIVersion string, objs ...interface{}) *conversion.ConversionReview {
+	req := &conversion.ConversionReview{
+		Request: &conversion.ConversionRequest{
+			UID:               "1234",
+			DesiredAPIVersion: desiredAPIVersion,
+		},
+	}
+	for _, obj := range objs {
+		raw, err := json.Marshal(obj)
+		require.NoError(t, err)
+		req.Request.Objects = append(req.Request.Objects, runtime.RawExtension{Raw: raw})
+	}
+	body, err := json.Marshal(req)
+	require.NoError(t, err)
+
+	w := httptest.NewRecorder()
+	(&conversion.Webhook{}).ServeHTTP(w, httptest.NewRequest(http.MethodPost, conversion.Path, bytes.NewReader(body)))
+	require.Equal(t, http.StatusOK, w.Code)
+
+	res := &conversion.ConversionReview{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), res))
+	require.Equal(t, req.Request.UID, res.Response.UID)
+	return res
+}
+
+func TestWebhook(t *testing.T) {
+	ring := &ringsv1alpha1.Ring{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ringsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Ring",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "query-v1-canary",
+			Namespace: "default",
+		},
+		Spec: ringsv1alpha1.RingSpec{
+			Deploy: true,
+			Routing: ringsv1alpha1.RingRouting{
+				Groups:  []ringsv1alpha1.RingGroup{{Name: "canary"}},
+				Hosts:   []string{"example.com"},
+				Service: "query",
+				Version: "v1",
+				Branch:  "canary",
+				Ports:   []ringsv1alpha1.RingPort{{Name: "default", Port: 80}},
+			},
+		},
+	}
+
+	// v1alpha1 Rings are converted to v1beta1
+	res := review(t, ringsv1beta1.SchemeGroupVersion.String(), ring)
+	require.Equal(t, metav1.StatusSuccess, res.Response.Result.Status)
+	require.Len(t, res.Response.ConvertedObjects, 1)
+	converted := &ringsv1beta1.Ring{}
+	require.NoError(t, json.Unmarshal(res.Response.ConvertedObjects[0].Raw, converted))
+	require.Equal(t, ringsv1beta1.SchemeGroupVersion.String(), converted.APIVersion)
+	require.Equal(t, "Ring", converted.Kind)
+	require.Equal(t, "query", converted.Spec.Backend.Service)
+	require.Equal(t, []string{"example.com"}, converted.Spec.Match.Hosts)
+	require.Equal(t, []ringsv1alpha1.RingGroup{{Name: "canary"}}, converted.Spec.Identity.Groups)
+
+	// And back to v1alpha1
+	res = review(t, ringsv1alpha1.SchemeGroupVersion.String(), converted)
+	require.Equal(t, metav1.StatusSuccess, res.Response.Result.Status)
+	roundTripped := &ringsv1alpha1.Ring{}
+	require.NoError(t, json.Unmarshal(res.Response.ConvertedObjects[0].Raw, roundTripped))
+	require.Equal(t, ring, roundTripped)
+
+	// Unknown versions fail the conversion
+	res = review(t, "rings.microsoft.com/v2", ring)
+	require.Equal(t, metav1.StatusFailure, res.Response.Result.Status)
+	require.Contains(t, res.Response.Result.Message, "unsupported Ring version rings.microsoft.com/v2")
+}
+
+func TestCABundleInjector(t *testing.T) {
+	gvk := schema.GroupVersionKind{Group: "apiextensions.k8s.io", Version: "v1beta1", Kind: "CustomResourceDefinition"}
+	s := runtime.NewScheme()
+	require.NoError(t, scheme.AddToScheme(s))
+	s.AddKnownTypeWithName(gvk, &unstructured.Unstructured{})
+
+	// The CRD is installed with the None strategy and v1alpha1 only served, whether the webhook is enabled or not
+	crd := &unstructured.Unstructured{Object: map[string]interface{}{
+		"spec": map[string]interface{}{
+			"conversion": map[string]interface{}{"strategy": "None"},
+			"versions": []interface{}{
+				map[string]interface{}{"name": "v1alpha1", "served": true, "storage": true},
+				map[string]interface{}{"name": "v1beta1", "served": false, "storage": false},
+			},
+		},
+	}}
+	crd.SetGroupVersionKind(gvk)
+	crd.SetName(conversion.CRDName)
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "webhook-server-secret", Namespace: "ring-operator"},
+		Data:       map[string][]byte{"ca-cert.pem": []byte("ca")},
+	}
+	cl := fake.NewFakeClientWithScheme(s, crd, secret)
+
+	injector := &conversion.CABundleInjector{
+		Client:  cl,
+		Secret:  types.NamespacedName{Namespace: "ring-operator", Name: "webhook-server-secret"},
+		Service: types.NamespacedName{Namespace: "ring-operator", Name: "webhook-server-service"},
+	}
+	err := injector.Start(make(chan struct{}))
+	require.NoError(t, err)
+
+	// The enabled webhook switches the CRD to its conversion
+	found := &unstructured.Unstructured{}
+	found.SetGroupVersionKind(gvk)
+	err = cl.Get(context.TODO(), types.NamespacedName{Name: conversion.CRDName}, found)
+	require.NoError(t, err)
+	strategy, _, _ := unstructured.NestedString(found.Object, "spec", "conversion", "strategy")
+	require.Equal(t, "Webhook", strategy)
+	caBundle, _, _ := unstructured.NestedString(found.Object, "spec", "conversion", "webhookClientConfig", "caBundle")
+	require.Equal(t, "Y2E=", caBundle)
+	path, _, _ := unstructured.NestedString(found.Object, "spec", "conversion", "webhookClientConfig", "service", "path")
+	require.Equal(t, conversion.Path, path)
+
+	// v1beta1 is served along with the conversion
+	versions, _, _ := unstructured.NestedSlice(found.Object, "spec", "versions")
+	require.Len(t, versions, 2)
+	require.Equal(t, true, versions[1].(map[string]interface{})["served"])
+	require.Equal(t, false, versions[1].(map[string]interface{})["storage"])
+}
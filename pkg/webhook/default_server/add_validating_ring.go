@@ -0,0 +1,26 @@
+package defaultserver
+
+import (
+	"fmt"
+
+	"github.com/microsoft/ring-operator/pkg/webhook/default_server/ring/validating"
+)
+
+func init() {
+	for k, v := range validating.Builders {
+		_, found := builderMap[k]
+		if found {
+			log.V(1).Info(fmt.Sprintf(
+				"conflicting webhook builder names in builder map: %v", k))
+		}
+		builderMap[k] = v
+	}
+	for k, v := range validating.HandlerMap {
+		_, found := HandlerMap[k]
+		if found {
+			log.V(1).Info(fmt.Sprintf(
+				"conflicting webhook builder names in handler map: %v", k))
+		}
+		HandlerMap[k] = v
+	}
+}
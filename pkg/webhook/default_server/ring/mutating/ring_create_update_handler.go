@@ -0,0 +1,75 @@
+package mutating
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+	"github.com/microsoft/ring-operator/pkg/controller/ring"
+	"github.com/microsoft/ring-operator/pkg/webhook/conversion"
+
+	"github.com/appscode/jsonpatch"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+func init() {
+	webhookName := "mutating-create-update-ring"
+	if HandlerMap[webhookName] == nil {
+		HandlerMap[webhookName] = []admission.Handler{}
+	}
+	HandlerMap[webhookName] = append(HandlerMap[webhookName], &RingCreateUpdateHandler{})
+}
+
+// RingCreateUpdateHandler fills in the defaults of the Rings so the stored Ring shows the routing
+// the operator applies
+type RingCreateUpdateHandler struct{}
+
+func (h *RingCreateUpdateHandler) mutatingRingFn(ctx context.Context, obj *ringsv1alpha1.Ring) error {
+	ring.DefaultRing(obj)
+	return nil
+}
+
+var _ admission.Handler = &RingCreateUpdateHandler{}
+
+// Handle handles admission requests.
+func (h *RingCreateUpdateHandler) Handle(ctx context.Context, req types.Request) types.Response {
+	// Rings of every version are defaulted as the v1alpha1 hub, the patch is made in the version of the request
+	// against the object of the request, the fields the round trip through the hub drops are patched as well
+	obj, err := conversion.ToHub(req.AdmissionRequest.Object.Raw)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+
+	err = h.mutatingRingFn(ctx, obj)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+
+	meta := &metav1.TypeMeta{}
+	if err := json.Unmarshal(req.AdmissionRequest.Object.Raw, meta); err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+	current, err := conversion.FromHub(obj, meta.APIVersion)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+	raw, err := json.Marshal(current)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+	patches, err := jsonpatch.CreatePatch(req.AdmissionRequest.Object.Raw, raw)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+	return types.Response{
+		Patches: patches,
+		Response: &admissionv1beta1.AdmissionResponse{
+			Allowed:   true,
+			PatchType: func() *admissionv1beta1.PatchType { pt := admissionv1beta1.PatchTypeJSONPatch; return &pt }(),
+		},
+	}
+}
@@ -0,0 +1,102 @@
+package mutating_test
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"testing"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+	ringsv1beta1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1beta1"
+	"github.com/microsoft/ring-operator/pkg/webhook/default_server/ring/mutating"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+func createRequest(t *testing.T, obj interface{}) types.Request {
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	return types.Request{AdmissionRequest: &admissionv1beta1.AdmissionRequest{
+		Operation: admissionv1beta1.Create,
+		Object:    runtime.RawExtension{Raw: raw},
+	}}
+}
+
+func TestRingCreateUpdateHandler(t *testing.T) {
+	h := &mutating.RingCreateUpdateHandler{}
+
+	os.Setenv("RING_DEFAULT_GROUP", "canary")
+	defer os.Unsetenv("RING_DEFAULT_GROUP")
+
+	ring := &ringsv1alpha1.Ring{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ringsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Ring",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "master",
+			Namespace: "default",
+			Labels:    map[string]string{"branch": "master"},
+		},
+		Spec: ringsv1alpha1.RingSpec{
+			Deploy: true,
+			Routing: ringsv1alpha1.RingRouting{
+				Service: "query",
+				Version: "v1",
+				Ports:   []ringsv1alpha1.RingPort{{Port: 80}},
+			},
+		},
+	}
+
+	// The defaults are patched into the ring
+	res := h.Handle(context.TODO(), createRequest(t, ring))
+	require.True(t, res.Response.Allowed)
+	patches := map[string]interface{}{}
+	for _, p := range res.Patches {
+		patches[p.Path] = p.Value
+	}
+	require.Equal(t, "master", patches["/spec/routing/branch"])
+	require.Equal(t, "default", patches["/spec/routing/ports/0/name"])
+	require.Equal(t, "TCP", patches["/spec/routing/ports/0/protocol"])
+	require.EqualValues(t, 80, patches["/spec/routing/ports/0/targetPort"])
+	require.Equal(t, []interface{}{map[string]interface{}{"name": "canary"}}, patches["/spec/routing/groups"])
+
+	// The values set on the ring are kept
+	ring.Spec.Routing.Branch = "beta"
+	ring.Spec.Routing.Groups = []ringsv1alpha1.RingGroup{{Name: "beta"}}
+	ring.Spec.Routing.Ports = []ringsv1alpha1.RingPort{{Name: "http", Protocol: "UDP", Port: 80, TargetPort: intstr.FromString("web")}}
+	res = h.Handle(context.TODO(), createRequest(t, ring))
+	require.True(t, res.Response.Allowed)
+	require.Empty(t, res.Patches)
+
+	// v1beta1 rings are patched in their own version
+	beta := &ringsv1beta1.Ring{}
+	ring.Spec.Routing.Ports[0].Protocol = ""
+	require.NoError(t, beta.ConvertFrom(ring))
+	res = h.Handle(context.TODO(), createRequest(t, beta))
+	require.True(t, res.Response.Allowed)
+	require.Len(t, res.Patches, 1)
+	require.Equal(t, "/spec/backend/ports/0/protocol", res.Patches[0].Path)
+	require.Equal(t, "TCP", res.Patches[0].Value)
+
+	// The patch is made against the object of the request, a field the Ring doesn't keep is removed by the
+	// patch rather than left out of it
+	raw, err := json.Marshal(beta)
+	require.NoError(t, err)
+	fields := map[string]interface{}{}
+	require.NoError(t, json.Unmarshal(raw, &fields))
+	fields["spec"].(map[string]interface{})["obsolete"] = true
+	res = h.Handle(context.TODO(), createRequest(t, fields))
+	require.True(t, res.Response.Allowed)
+	paths := map[string]string{}
+	for _, p := range res.Patches {
+		paths[p.Path] = p.Operation
+	}
+	require.Equal(t, map[string]string{"/spec/backend/ports/0/protocol": "add", "/spec/obsolete": "remove"}, paths)
+}
@@ -0,0 +1,104 @@
+package validating
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+	"github.com/microsoft/ring-operator/pkg/controller/ring"
+	"github.com/microsoft/ring-operator/pkg/webhook/conversion"
+
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/runtime/inject"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+func init() {
+	webhookName := "validating-create-update-ring"
+	if HandlerMap[webhookName] == nil {
+		HandlerMap[webhookName] = []admission.Handler{}
+	}
+	HandlerMap[webhookName] = append(HandlerMap[webhookName], &RingCreateUpdateHandler{})
+}
+
+// RingCreateUpdateHandler rejects the Rings which can't be reconciled, the updates of their immutable
+// fields and the Rings the controller wouldn't route as another Ring already claims their route
+type RingCreateUpdateHandler struct {
+	// Client reads the other Rings of the cluster
+	Client client.Client
+	// Router validates the Rings as the controller routes them, it is injected once its APIs are detected
+	Router ring.Router
+}
+
+func (h *RingCreateUpdateHandler) validatingRingFn(ctx context.Context, obj *ringsv1alpha1.Ring, old *ringsv1alpha1.Ring) (bool, string, error) {
+	// Rings being deleted and the updates leaving the spec alone, such as the finalizer of the controller, are
+	// admitted so a Ring which lost its route or predates the rules can always be deleted
+	if obj.DeletionTimestamp != nil || (old != nil && equality.Semantic.DeepEqual(obj.Spec, old.Spec)) {
+		return true, "allowed to be admitted", nil
+	}
+
+	errs := ring.ValidateRing(obj, h.Router)
+	if old != nil {
+		errs = append(errs, ring.ValidateRingUpdate(obj, old)...)
+	}
+	if len(errs) > 0 {
+		return false, errs.ToAggregate().Error(), nil
+	}
+
+	// Rings which are not active have no route to conflict with
+	other, err := ring.FindRouteConflict(h.Client, obj)
+	if err != nil {
+		return false, "", err
+	}
+	if other != nil {
+		return false, fmt.Sprintf("ring %s/%s already routes service %s version %s for the same path, hosts and groups",
+			other.Namespace, other.Name, obj.Spec.Routing.Service, obj.Spec.Routing.Version), nil
+	}
+	return true, "allowed to be admitted", nil
+}
+
+var _ admission.Handler = &RingCreateUpdateHandler{}
+
+// Handle handles admission requests.
+func (h *RingCreateUpdateHandler) Handle(ctx context.Context, req types.Request) types.Response {
+	if h.Router == nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, fmt.Errorf("no router to validate the ring with"))
+	}
+
+	// Rings of every version are validated as the v1alpha1 hub
+	obj, err := conversion.ToHub(req.AdmissionRequest.Object.Raw)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusBadRequest, err)
+	}
+
+	var old *ringsv1alpha1.Ring
+	if req.AdmissionRequest.Operation == admissionv1beta1.Update {
+		if old, err = conversion.ToHub(req.AdmissionRequest.OldObject.Raw); err != nil {
+			return admission.ErrorResponse(http.StatusBadRequest, err)
+		}
+	}
+
+	allowed, reason, err := h.validatingRingFn(ctx, obj, old)
+	if err != nil {
+		return admission.ErrorResponse(http.StatusInternalServerError, err)
+	}
+	return admission.ValidationResponse(allowed, reason)
+}
+
+var _ inject.Client = &RingCreateUpdateHandler{}
+
+// InjectClient injects the client into the RingCreateUpdateHandler
+func (h *RingCreateUpdateHandler) InjectClient(c client.Client) error {
+	h.Client = c
+	return nil
+}
+
+// InjectRouter injects the router into the RingCreateUpdateHandler
+func (h *RingCreateUpdateHandler) InjectRouter(router ring.Router) error {
+	h.Router = router
+	return nil
+}
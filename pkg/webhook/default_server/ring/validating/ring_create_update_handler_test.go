@@ -0,0 +1,171 @@
+package validating_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	ringsv1alpha1 "github.com/microsoft/ring-operator/pkg/apis/rings/v1alpha1"
+	"github.com/microsoft/ring-operator/pkg/controller/ring"
+	"github.com/microsoft/ring-operator/pkg/webhook/default_server/ring/validating"
+
+	"github.com/stretchr/testify/require"
+	admissionv1beta1 "k8s.io/api/admission/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	fakediscovery "k8s.io/client-go/discovery/fake"
+	"k8s.io/client-go/kubernetes/scheme"
+	clienttesting "k8s.io/client-go/testing"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/types"
+)
+
+func createRing(name, group string) *ringsv1alpha1.Ring {
+	return &ringsv1alpha1.Ring{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: ringsv1alpha1.SchemeGroupVersion.String(),
+			Kind:       "Ring",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: "default",
+		},
+		Spec: ringsv1alpha1.RingSpec{
+			Deploy: true,
+			Routing: ringsv1alpha1.RingRouting{
+				Groups:  []ringsv1alpha1.RingGroup{{Name: group}},
+				Service: "query",
+				Version: "v1",
+				Branch:  name,
+				Ports:   []ringsv1alpha1.RingPort{{Name: "default", Port: 80}},
+			},
+		},
+	}
+}
+
+func createRequest(t *testing.T, op admissionv1beta1.Operation, obj, old *ringsv1alpha1.Ring) types.Request {
+	raw, err := json.Marshal(obj)
+	require.NoError(t, err)
+
+	req := &admissionv1beta1.AdmissionRequest{
+		Operation: op,
+		Object:    runtime.RawExtension{Raw: raw},
+	}
+	if old != nil {
+		oldRaw, err := json.Marshal(old)
+		require.NoError(t, err)
+		req.OldObject = runtime.RawExtension{Raw: oldRaw}
+	}
+	return types.Request{AdmissionRequest: req}
+}
+
+func TestRingCreateUpdateHandler(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+
+	existing := createRing("master", "canary")
+	existing.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Hour))
+	late := createRing("late", "canary")
+	late.Namespace = "other"
+	late.CreationTimestamp = metav1.NewTime(time.Now().Add(-time.Minute))
+	router, err := ring.NewRouter("traefik")
+	require.NoError(t, err)
+	h := &validating.RingCreateUpdateHandler{
+		Client: fake.NewFakeClient(existing, late),
+		Router: router,
+	}
+
+	// A valid ring routed to another group is admitted
+	res := h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Create, createRing("beta", "beta"), nil))
+	require.True(t, res.Response.Allowed, res.Response.Result.Reason)
+
+	// Empty service, missing ports and backticks in group names are rejected
+	invalid := createRing("beta", "be`ta")
+	invalid.Spec.Routing.Service = ""
+	invalid.Spec.Routing.Ports = nil
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Create, invalid, nil))
+	require.False(t, res.Response.Allowed)
+	reason := string(res.Response.Result.Reason)
+	require.Contains(t, reason, "spec.routing.service: Required value")
+	require.Contains(t, reason, "spec.routing.ports: Required value")
+	require.Contains(t, reason, "spec.routing.groups[0].name")
+
+	// Duplicate port names are rejected
+	duplicate := createRing("beta", "beta")
+	duplicate.Spec.Routing.Ports = append(duplicate.Spec.Routing.Ports, ringsv1alpha1.RingPort{Name: "default", Port: 8080})
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Create, duplicate, nil))
+	require.False(t, res.Response.Allowed)
+	require.Contains(t, string(res.Response.Result.Reason), "spec.routing.ports[1].name: Duplicate value")
+
+	// A ring routed on the same service, version and group as another ring is rejected
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Create, createRing("beta", "canary"), nil))
+	require.False(t, res.Response.Allowed)
+	require.Contains(t, string(res.Response.Result.Reason), "ring default/master already routes service query version v1")
+
+	// The oldest ring keeps its route, like the controller routes it, whatever its namespace
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Create, createRing("gamma", "canary"), nil))
+	require.False(t, res.Response.Allowed)
+	relabeled := existing.DeepCopy()
+	relabeled.Labels = map[string]string{"team": "query"}
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Update, relabeled, existing))
+	require.True(t, res.Response.Allowed, res.Response.Result.Reason)
+	moved := late.DeepCopy()
+	moved.Spec.Routing.Hosts = []string{"api.example.com"}
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Update, late, moved))
+	require.False(t, res.Response.Allowed)
+	require.Contains(t, string(res.Response.Result.Reason), "ring default/master already routes")
+
+	// The ring which lost its route can still have its finalizer removed and be deleted
+	finalized := late.DeepCopy()
+	finalized.Finalizers = []string{"finalizer.rings.microsoft.com"}
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Update, late, finalized))
+	require.True(t, res.Response.Allowed, res.Response.Result.Reason)
+	deleted := moved.DeepCopy()
+	deleted.DeletionTimestamp = &metav1.Time{Time: time.Now()}
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Update, deleted, late))
+	require.True(t, res.Response.Allowed, res.Response.Result.Reason)
+
+	// Immutable fields can't be updated
+	updated := existing.DeepCopy()
+	updated.Spec.Routing.Version = "v2"
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Update, updated, existing))
+	require.False(t, res.Response.Allowed)
+	require.Contains(t, string(res.Response.Result.Reason), "spec.routing.version: Forbidden: field is immutable")
+}
+
+func TestRingCreateUpdateHandlerDetectedRouter(t *testing.T) {
+	s := scheme.Scheme
+	s.AddKnownTypes(ringsv1alpha1.SchemeGroupVersion, &ringsv1alpha1.Ring{}, &ringsv1alpha1.RingList{})
+
+	whiteListed := createRing("beta", "beta")
+	whiteListed.Spec.Routing.Middlewares = []ringsv1alpha1.RingMiddleware{
+		{Name: "internal", Spec: &runtime.RawExtension{Raw: []byte(`{"ipWhiteList":{"sourceRange":["10.0.0.0/8"]}}`)}},
+	}
+
+	// The ipWhiteList middlewares are admitted by a router which didn't detect Traefik v3
+	router, err := ring.NewRouter("traefik")
+	require.NoError(t, err)
+	h := &validating.RingCreateUpdateHandler{Client: fake.NewFakeClient()}
+	err = h.InjectRouter(router)
+	require.NoError(t, err)
+	res := h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Create, whiteListed, nil))
+	require.True(t, res.Response.Allowed, res.Response.Result.Reason)
+
+	// They are rejected once the router detected the cluster serves Traefik v3, as the controller would
+	router, err = ring.NewRouter("traefik")
+	require.NoError(t, err)
+	err = router.(ring.APIDetector).DetectAPIs(&fakediscovery.FakeDiscovery{Fake: &clienttesting.Fake{Resources: []*metav1.APIResourceList{{
+		GroupVersion: "traefik.io/v1alpha1",
+		APIResources: []metav1.APIResource{
+			{Name: "ingressroutes", Kind: "IngressRoute", Namespaced: true},
+			{Name: "serverstransporttcps", Kind: "ServersTransportTCP", Namespaced: true},
+		},
+	}}}})
+	require.NoError(t, err)
+	err = h.InjectRouter(router)
+	require.NoError(t, err)
+	res = h.Handle(context.TODO(), createRequest(t, admissionv1beta1.Create, whiteListed, nil))
+	require.False(t, res.Response.Allowed)
+	require.Contains(t, string(res.Response.Result.Reason), "ipWhiteList middlewares are not served by Traefik v3")
+}
@@ -0,0 +1,19 @@
+package validating
+
+import (
+	"github.com/microsoft/ring-operator/pkg/webhook/conversion"
+
+	admissionregistrationv1beta1 "k8s.io/api/admissionregistration/v1beta1"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/builder"
+)
+
+func init() {
+	builderName := "validating-create-update-ring"
+	Builders[builderName] = builder.
+		NewWebhookBuilder().
+		Name(builderName + ".rings.microsoft.com").
+		Path("/" + builderName).
+		Validating().
+		Rules(conversion.Rules(admissionregistrationv1beta1.Create, admissionregistrationv1beta1.Update)...).
+		FailurePolicy(admissionregistrationv1beta1.Fail)
+}
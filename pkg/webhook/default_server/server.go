@@ -0,0 +1,130 @@
+package defaultserver
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/microsoft/ring-operator/pkg/controller/ring"
+	"github.com/microsoft/ring-operator/pkg/webhook/conversion"
+
+	"github.com/operator-framework/operator-sdk/pkg/k8sutil"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	logf "sigs.k8s.io/controller-runtime/pkg/runtime/log"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission/builder"
+)
+
+var (
+	log        = logf.Log.WithName("default_server")
+	builderMap = map[string]*builder.WebhookBuilder{}
+	// HandlerMap contains all admission webhook handlers.
+	HandlerMap = map[string][]admission.Handler{}
+)
+
+// routerInjector is implemented by the handlers validating the Rings with the router of the operator
+type routerInjector interface {
+	InjectRouter(router ring.Router) error
+}
+
+const (
+	serverName  = "ring-operator-webhook-server"
+	certDir     = "/tmp/cert"
+	serverPort  = 9876
+	secretName  = "ring-operator-webhook-server-secret"
+	serviceName = "ring-operator-webhook-server-service"
+)
+
+// Add adds itself to the manager when RING_WEBHOOK_ENABLED is set
+// The server provisions its certificate in a Secret and registers the webhook configurations
+// pointing at a Service in front of the operator
+func Add(mgr manager.Manager) error {
+	if os.Getenv("RING_WEBHOOK_ENABLED") != "true" {
+		log.Info("Webhook server is disabled, set RING_WEBHOOK_ENABLED to enable it")
+		return nil
+	}
+
+	namespace, err := k8sutil.GetOperatorNamespace()
+	if err != nil {
+		return fmt.Errorf("could not get the operator namespace for the webhook server: %v", err)
+	}
+
+	svr, err := webhook.NewServer(serverName, mgr, webhook.ServerOptions{
+		Port:    serverPort,
+		CertDir: certDir,
+		BootstrapOptions: &webhook.BootstrapOptions{
+			ValidatingWebhookConfigName: "ring-operator-validating-webhook",
+			MutatingWebhookConfigName:   "ring-operator-mutating-webhook",
+			Secret: &types.NamespacedName{
+				Namespace: namespace,
+				Name:      secretName,
+			},
+			Service: &webhook.Service{
+				Namespace: namespace,
+				Name:      serviceName,
+				// The selector of the operator Deployment
+				Selectors: map[string]string{
+					"name": "ring-operator",
+				},
+			},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	// The APIs served for the router are detected once, the Rings are validated with the rule syntax and
+	// API groups the controller routes them with
+	router, err := ring.NewRouterForConfig(mgr.GetConfig())
+	if err != nil {
+		return fmt.Errorf("could not configure the router of the webhook server: %v", err)
+	}
+
+	var webhooks []webhook.Webhook
+	for k, b := range builderMap {
+		handlers, ok := HandlerMap[k]
+		if !ok {
+			log.V(1).Info(fmt.Sprintf("can't find handlers for builder: %v", k))
+			handlers = []admission.Handler{}
+		}
+		for _, h := range handlers {
+			if injector, ok := h.(routerInjector); ok {
+				if err := injector.InjectRouter(router); err != nil {
+					return err
+				}
+			}
+		}
+		wh, err := b.
+			Handlers(handlers...).
+			WithManager(mgr).
+			Build()
+		if err != nil {
+			return err
+		}
+		webhooks = append(webhooks, wh)
+	}
+
+	if err := svr.Register(webhooks...); err != nil {
+		return err
+	}
+
+	// The Ring CRD serves v1alpha1 and v1beta1 through the conversion webhook of the server
+	svr.Handle(conversion.Path, &conversion.Webhook{})
+	c, err := client.New(mgr.GetConfig(), client.Options{Scheme: mgr.GetScheme(), Mapper: mgr.GetRESTMapper()})
+	if err != nil {
+		return err
+	}
+	return mgr.Add(&conversion.CABundleInjector{
+		Client: c,
+		Secret: types.NamespacedName{
+			Namespace: namespace,
+			Name:      secretName,
+		},
+		Service: types.NamespacedName{
+			Namespace: namespace,
+			Name:      serviceName,
+		},
+	})
+}